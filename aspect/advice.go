@@ -1,7 +1,13 @@
 // Package aspect - advice defines the advice types and execution chain for AOP
 package aspect
 
-import "sort"
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
 
 // -------------------------------------------- Constants & Variables --------------------------------------------
 
@@ -24,9 +30,11 @@ type AdviceFunc func(ctx *Context) error
 
 // Advice represents a single piece of advice attached to a function.
 type Advice struct {
+	Name     string // Name identifies the advice for runtime switches (optional).
 	Type     AdviceType
 	Handler  AdviceFunc
-	Priority int // Higher priority executes first (for same type).
+	Priority int       // Higher priority executes first (for same type).
+	Closer   io.Closer // Closer releases advice state on Registry.Shutdown (optional).
 }
 
 // AdviceChain manages a collection of advice for a single function.
@@ -36,6 +44,27 @@ type AdviceChain struct {
 	around         []Advice
 	afterReturning []Advice
 	afterThrowing  []Advice
+
+	mu        sync.Mutex                    // mu serializes Add against compilation.
+	compiled  atomic.Pointer[compiledChain] // compiled caches the priority-sorted advice lists.
+	frozen    atomic.Bool                   // frozen rejects further Add calls.
+	inflight  atomic.Int64                  // inflight counts active invocations of the function.
+	calls     atomic.Uint64                 // calls counts invocations run with advice.
+	errors    atomic.Uint64                 // errors counts invocations that returned with a Context error.
+	panics    atomic.Uint64                 // panics counts invocations that panicked.
+	disabled  atomic.Bool                   // disabled bypasses all advice for the function.
+	tags      map[string]string             // tags are set at registration and read-only afterwards.
+	redaction redaction                     // redaction is set at registration and read-only afterwards.
+	switches  *switchboard                  // switches holds the owning registry's runtime switches (nil for standalone chains).
+}
+
+// compiledChain is an immutable, priority-sorted snapshot of an AdviceChain.
+type compiledChain struct {
+	before         []Advice
+	after          []Advice
+	around         []Advice
+	afterReturning []Advice
+	afterThrowing  []Advice
 }
 
 // NewAdviceChain creates a new empty advice chain.
@@ -51,8 +80,34 @@ func NewAdviceChain() *AdviceChain {
 
 // -------------------------------------------- Public Functions --------------------------------------------
 
+// String returns the advice type name implementing fmt.Stringer interface.
+func (adviceType AdviceType) String() string {
+	switch adviceType {
+	case Before:
+		return "Before"
+	case After:
+		return "After"
+	case Around:
+		return "Around"
+	case AfterReturning:
+		return "AfterReturning"
+	case AfterThrowing:
+		return "AfterThrowing"
+	default:
+		return "Unknown"
+	}
+}
+
 // Add adds advice to the chain based on its type.
+// Panics with ErrRegistryFrozen if the owning registry has been frozen.
 func (ac *AdviceChain) Add(advice Advice) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.frozen.Load() {
+		panic(ErrRegistryFrozen)
+	}
+
 	switch advice.Type {
 	case Before:
 		ac.before = append(ac.before, advice)
@@ -65,61 +120,182 @@ func (ac *AdviceChain) Add(advice Advice) {
 	case AfterThrowing:
 		ac.afterThrowing = append(ac.afterThrowing, advice)
 	}
+	ac.compiled.Store(nil)
+}
+
+// Remove detaches every advice with the given name and returns the removed advice.
+// Panics with ErrRegistryFrozen if the owning registry has been frozen.
+func (ac *AdviceChain) Remove(name string) []Advice {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if ac.frozen.Load() {
+		panic(ErrRegistryFrozen)
+	}
+
+	var removed []Advice
+	keep := func(adviceList []Advice) []Advice {
+		kept := make([]Advice, 0, len(adviceList))
+		for _, advice := range adviceList {
+			if advice.Name == name {
+				removed = append(removed, advice)
+				continue
+			}
+			kept = append(kept, advice)
+		}
+		return kept
+	}
+
+	ac.before = keep(ac.before)
+	ac.after = keep(ac.after)
+	ac.around = keep(ac.around)
+	ac.afterReturning = keep(ac.afterReturning)
+	ac.afterThrowing = keep(ac.afterThrowing)
+	ac.compiled.Store(nil)
+	return removed
 }
 
 // ExecuteBefore runs all Before advice in order of priority.
 func (ac *AdviceChain) ExecuteBefore(ctx *Context) error {
-	return ac.executeAdviceList(ac.before, ctx)
+	return ac.executeAdviceList(ac.compile().before, ctx)
 }
 
 // ExecuteAfter runs all After advice in order of priority.
 func (ac *AdviceChain) ExecuteAfter(ctx *Context) error {
-	return ac.executeAdviceList(ac.after, ctx)
+	return ac.executeAdviceList(ac.compile().after, ctx)
 }
 
 // ExecuteAround runs all Around advice in order of priority.
 func (ac *AdviceChain) ExecuteAround(ctx *Context) error {
-	return ac.executeAdviceList(ac.around, ctx)
+	return ac.executeAdviceList(ac.compile().around, ctx)
 }
 
 // ExecuteAfterReturning runs all AfterReturning advice in order of priority.
 func (ac *AdviceChain) ExecuteAfterReturning(ctx *Context) error {
-	return ac.executeAdviceList(ac.afterReturning, ctx)
+	return ac.executeAdviceList(ac.compile().afterReturning, ctx)
 }
 
 // ExecuteAfterThrowing runs all AfterThrowing advice in order of priority.
 func (ac *AdviceChain) ExecuteAfterThrowing(ctx *Context) error {
-	return ac.executeAdviceList(ac.afterThrowing, ctx)
+	return ac.executeAdviceList(ac.compile().afterThrowing, ctx)
+}
+
+// executeAround runs Around advice as an interceptor chain that ends in the target function.
+// Advice that calls ctx.Proceed runs the rest of the chain itself; advice that returns without
+// proceeding falls through to the next Around advice, and the target is skipped if ctx.Skipped is set.
+func (ac *AdviceChain) executeAround(ctx *Context, target func(*Context)) error {
+	return ac.proceedFrom(ac.compile().around, 0, ctx, target)
 }
 
 // HasAround returns true if the chain has Around advice.
 func (ac *AdviceChain) HasAround() bool {
-	return len(ac.around) > 0
+	return len(ac.compile().around) > 0
 }
 
 // Count returns the total number of advice in the chain.
 func (ac *AdviceChain) Count() int {
-	return len(ac.before) + len(ac.after) + len(ac.around) + len(ac.afterReturning) + len(ac.afterThrowing)
+	compiled := ac.compile()
+	return len(compiled.before) + len(compiled.after) + len(compiled.around) + len(compiled.afterReturning) + len(compiled.afterThrowing)
 }
 
 // -------------------------------------------- Private Helper Functions --------------------------------------------
 
-// executeAdviceList runs a list of advice in priority order.
-func (ac *AdviceChain) executeAdviceList(adviceList []Advice, ctx *Context) error {
-	if len(adviceList) == 0 {
-		return nil
+// compile returns the sorted snapshot of the chain, building it on first use after a change.
+func (ac *AdviceChain) compile() *compiledChain {
+	if compiled := ac.compiled.Load(); compiled != nil {
+		return compiled
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	if compiled := ac.compiled.Load(); compiled != nil {
+		return compiled
 	}
+	compiled := &compiledChain{
+		before:         sortByPriority(ac.before),
+		after:          sortByPriority(ac.after),
+		around:         sortByPriority(ac.around),
+		afterReturning: sortByPriority(ac.afterReturning),
+		afterThrowing:  sortByPriority(ac.afterThrowing),
+	}
+	ac.compiled.Store(compiled)
+	return compiled
+}
+
+// freeze precompiles the chain and rejects further additions.
+func (ac *AdviceChain) freeze() {
+	ac.compile()
+	ac.frozen.Store(true)
+}
 
-	// Sort by priority (highest first)
+// sortByPriority returns a copy of the advice list sorted by priority (highest first).
+// Advice with equal priority keep their insertion order.
+func sortByPriority(adviceList []Advice) []Advice {
 	sortedAdviceList := make([]Advice, len(adviceList))
 	copy(sortedAdviceList, adviceList)
 
-	sort.Slice(sortedAdviceList, func(i, j int) bool {
+	sort.SliceStable(sortedAdviceList, func(i, j int) bool {
 		return sortedAdviceList[i].Priority > sortedAdviceList[j].Priority
 	})
+	return sortedAdviceList
+}
+
+// proceedFrom runs Around advice starting at index, then the target function.
+func (ac *AdviceChain) proceedFrom(adviceList []Advice, index int, ctx *Context, target func(*Context)) error {
+	disabledAdvice := ac.switches.disabledAdvice()
+	for index < len(adviceList) {
+		if _, off := disabledAdvice[adviceList[index].Name]; !off || adviceList[index].Name == "" {
+			break
+		}
+		index++
+	}
+
+	if index == len(adviceList) {
+		if !ctx.Skipped {
+			target(ctx)
+		}
+		return nil
+	}
 
-	// Execute in order
-	for _, advice := range sortedAdviceList {
+	// proceeded is atomic since forks of ctx may proceed from other goroutines
+	var proceeded atomic.Bool
+	previous := ctx.proceed
+	ctx.proceed = func(invocation *Context) error {
+		proceeded.Store(true)
+		if err := ac.proceedFrom(adviceList, index+1, invocation, target); err != nil {
+			panic(fmt.Errorf("around advice failed: %w", err))
+		}
+		return invocation.Error
+	}
+
+	ctx.track(adviceList[index])
+	err := adviceList[index].Handler(ctx)
+	ctx.proceed = previous
+	if err != nil {
+		return err
+	}
+
+	// Legacy Around advice that neither proceeded nor skipped falls through to the rest of the chain
+	if proceeded.Load() || ctx.Skipped {
+		return nil
+	}
+	return ac.proceedFrom(adviceList, index+1, ctx, target)
+}
+
+// executeAdviceList runs a pre-sorted list of advice in order.
+func (ac *AdviceChain) executeAdviceList(adviceList []Advice, ctx *Context) error {
+	if len(adviceList) == 0 {
+		return nil
+	}
+
+	// Execute in order, skipping advice switched off at runtime
+	disabledAdvice := ac.switches.disabledAdvice()
+	for _, advice := range adviceList {
+		if _, off := disabledAdvice[advice.Name]; off && advice.Name != "" {
+			continue
+		}
+		ctx.track(advice)
 		if err := advice.Handler(ctx); err != nil {
 			return err
 		}
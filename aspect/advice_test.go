@@ -45,6 +45,37 @@ func TestAdviceChain_ExecutionOrder(t *testing.T) {
 	}
 }
 
+func TestAdviceChain_RecompilesAfterAdd(t *testing.T) {
+	chain := NewAdviceChain()
+	var order []string
+
+	chain.Add(Advice{
+		Type:     Before,
+		Priority: 10,
+		Handler: func(ctx *Context) error {
+			order = append(order, "low")
+			return nil
+		},
+	})
+	_ = chain.ExecuteBefore(NewContext("test"))
+
+	// Adding after a compiled execution must invalidate the cached order
+	chain.Add(Advice{
+		Type:     Before,
+		Priority: 20,
+		Handler: func(ctx *Context) error {
+			order = append(order, "high")
+			return nil
+		},
+	})
+	order = nil
+	_ = chain.ExecuteBefore(NewContext("test"))
+
+	if len(order) != 2 || order[0] != "high" || order[1] != "low" {
+		t.Fatalf("expected [high low], got %v", order)
+	}
+}
+
 func TestAdviceChain_ErrorPropagation(t *testing.T) {
 	chain := NewAdviceChain()
 
@@ -308,3 +339,31 @@ func TestAround_SkipExecution(t *testing.T) {
 
 	Unregister("TestAround")
 }
+
+func TestContext_TrackAdvice(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Tracked")
+	noop := func(ctx *Context) error { return nil }
+	registry.MustAddAdvice("Tracked", Advice{Name: "untracked", Type: Before, Priority: 20, Handler: noop})
+	registry.MustAddAdvice("Tracked", Advice{Name: "tracker", Type: Before, Priority: 10, Handler: func(ctx *Context) error {
+		ctx.TrackAdvice()
+		return nil
+	}})
+	registry.MustAddAdvice("Tracked", Advice{Name: "around", Type: Around, Handler: func(ctx *Context) error {
+		_ = ctx.Proceed()
+		return nil
+	}})
+
+	var executed []ExecutedAdvice
+	registry.MustAddAdvice("Tracked", Advice{Name: "after", Type: After, Handler: func(ctx *Context) error {
+		executed = ctx.ExecutedAdvice()
+		return nil
+	}})
+
+	Wrap0("Tracked", func() {})()
+
+	// Advice run before TrackAdvice, including the tracker itself, is not recorded
+	if len(executed) != 2 || executed[0].Name != "around" || executed[0].Type != Around || executed[1].Name != "after" {
+		t.Fatalf("expected the around and after advice, got %+v", executed)
+	}
+}
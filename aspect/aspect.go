@@ -0,0 +1,159 @@
+// Package aspect - aspect bundles stateful advice with lifecycle hooks into reusable units
+package aspect
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Aspect bundles several advice (e.g. Before + AfterReturning + AfterThrowing) together with
+// the state they share, so patterns like circuit breakers or caches do not need package globals.
+type Aspect interface {
+	// Name identifies the aspect; advice without a name inherit it, so DisableAdvice(Name())
+	// switches the whole aspect off.
+	Name() string
+	// Advice returns the advice attached to every function selected by the pointcut.
+	Advice() []Advice
+	// Init prepares the aspect's state; called once per registry before the aspect is first applied.
+	Init() error
+	// Close releases the aspect's state; called once on Registry.Shutdown.
+	Close() error
+}
+
+// RegistryAware is implemented by aspects that need the registry they are applied to,
+// e.g. to publish gauges. Attach is called once per registry, right before Init.
+type RegistryAware interface {
+	Attach(registry *Registry)
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Apply initializes the aspect (once per registry) and attaches its advice to every
+// registered function selected by the pointcut. Functions registered later are not affected.
+// The advice is attached to all selected functions or, on error, to none of them.
+// Returns error if the pointcut matches nothing, Init fails, or the registry is frozen.
+func (registry *Registry) Apply(pointcut Pointcut, aspect Aspect) error {
+	if registry.IsFrozen() {
+		return ErrRegistryFrozen
+	}
+
+	functionNames := registry.Select(pointcut)
+	if len(functionNames) == 0 {
+		return fmt.Errorf("aspect '%s': pointcut matched no registered functions", aspect.Name())
+	}
+
+	initialized, err := registry.initAspect(aspect)
+	if err != nil {
+		return fmt.Errorf("aspect '%s' init failed: %w", aspect.Name(), err)
+	}
+
+	// Copied so the aspect's own advice is not modified
+	adviceList := slices.Clone(aspect.Advice())
+	for index := range adviceList {
+		if adviceList[index].Name == "" {
+			adviceList[index].Name = aspect.Name()
+		}
+	}
+	if err := registry.addAdviceToAll(functionNames, adviceList); err != nil {
+		if initialized {
+			err = errors.Join(err, registry.discardAspect(aspect))
+		}
+		return fmt.Errorf("aspect '%s': %w", aspect.Name(), err)
+	}
+	return nil
+}
+
+// MustApply applies an aspect and panics on error.
+func (registry *Registry) MustApply(pointcut Pointcut, aspect Aspect) {
+	if err := registry.Apply(pointcut, aspect); err != nil {
+		panic(err)
+	}
+}
+
+// Aspects returns the aspects applied to the registry in application order.
+func (registry *Registry) Aspects() []Aspect {
+	registry.aspectMu.Lock()
+	defer registry.aspectMu.Unlock()
+	return append([]Aspect(nil), registry.aspects...)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// initAspect calls Init the first time an aspect instance is applied to this registry, reporting
+// whether it did.
+func (registry *Registry) initAspect(aspect Aspect) (bool, error) {
+	registry.aspectMu.Lock()
+	defer registry.aspectMu.Unlock()
+
+	for _, existing := range registry.aspects {
+		if sameInstance(existing, aspect) {
+			return false, nil
+		}
+	}
+
+	if aware, ok := aspect.(RegistryAware); ok {
+		aware.Attach(registry)
+	}
+	if err := aspect.Init(); err != nil {
+		return false, err
+	}
+	registry.aspects = append(registry.aspects, aspect)
+	return true, nil
+}
+
+// discardAspect closes an aspect initialized by an Apply that failed and forgets it, so a later
+// Apply initializes it again.
+func (registry *Registry) discardAspect(aspect Aspect) error {
+	registry.aspectMu.Lock()
+	registry.aspects = slices.DeleteFunc(registry.aspects, func(existing Aspect) bool {
+		return sameInstance(existing, aspect)
+	})
+	registry.aspectMu.Unlock()
+	return aspect.Close()
+}
+
+// addAdviceToAll attaches the advice to every function, checking all of them first
+// so a failure leaves no function partially advised.
+func (registry *Registry) addAdviceToAll(functionNames []string, adviceList []Advice) error {
+	events := registry.batch()
+	defer events.flush()
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
+	chains := make([]*AdviceChain, 0, len(functionNames))
+	for _, functionName := range functionNames {
+		chain, exists := registry.entries[functionName]
+		if !exists {
+			return fmt.Errorf("function '%s' is not registered", functionName)
+		}
+		chains = append(chains, chain)
+	}
+
+	for index, chain := range chains {
+		for _, advice := range adviceList {
+			chain.Add(advice)
+			events.add(Event{Type: AdviceAdded, FunctionName: functionNames[index], AdviceName: advice.Name, AdviceType: advice.Type})
+		}
+	}
+	return nil
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// Apply applies an aspect to functions of the global registry.
+func Apply(pointcut Pointcut, aspect Aspect) error {
+	return globalRegistry.Apply(pointcut, aspect)
+}
+
+// MustApply applies an aspect to the global registry and panics on error.
+func MustApply(pointcut Pointcut, aspect Aspect) {
+	globalRegistry.MustApply(pointcut, aspect)
+}
@@ -0,0 +1,246 @@
+// Package aspect - aspect_test validates aspects, pointcuts and tags
+package aspect
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// failureCounter is a stateful aspect counting failures per function.
+type failureCounter struct {
+	mu       sync.Mutex
+	failures map[string]int
+	inits    int
+	closes   int
+}
+
+func (counter *failureCounter) Name() string { return "failure-counter" }
+
+func (counter *failureCounter) Init() error {
+	counter.inits++
+	counter.failures = make(map[string]int)
+	return nil
+}
+
+func (counter *failureCounter) Close() error {
+	counter.closes++
+	return nil
+}
+
+func (counter *failureCounter) Advice() []Advice {
+	return []Advice{
+		{
+			Type:     After,
+			Priority: 100,
+			Handler: func(ctx *Context) error {
+				if ctx.Error != nil {
+					counter.mu.Lock()
+					counter.failures[ctx.FunctionName]++
+					counter.mu.Unlock()
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// sessionTable is a non-comparable aspect; applying the same map twice must still Init it once.
+type sessionTable map[string]int
+
+func (table sessionTable) Name() string { return "session-table" }
+
+func (table sessionTable) Init() error {
+	table["inits"]++
+	return nil
+}
+
+func (table sessionTable) Close() error {
+	table["closes"]++
+	return nil
+}
+
+func (table sessionTable) Advice() []Advice {
+	return []Advice{{Type: Before, Handler: func(ctx *Context) error { return nil }}}
+}
+
+// storedAdvice is an aspect returning the same advice slice on every call.
+type storedAdvice struct {
+	advice        []Advice
+	onInit        func()
+	inits, closes int
+}
+
+func (stored *storedAdvice) Name() string { return "stored-advice" }
+
+func (stored *storedAdvice) Init() error {
+	stored.inits++
+	if stored.onInit != nil {
+		stored.onInit()
+	}
+	return nil
+}
+
+func (stored *storedAdvice) Close() error {
+	stored.closes++
+	return nil
+}
+
+func (stored *storedAdvice) Advice() []Advice { return stored.advice }
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestAspect_ApplyToPointcut(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("FetchUser", WithTags(map[string]string{"layer": "repository"}))
+	registry.MustRegister("FetchOrder", WithTags(map[string]string{"layer": "repository"}))
+	registry.MustRegister("RenderPage", WithTags(map[string]string{"layer": "web"}))
+
+	counter := &failureCounter{}
+	if err := registry.Apply(Tagged("layer", "repository"), counter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Apply(On("RenderPage"), counter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if counter.inits != 1 {
+		t.Fatalf("expected Init once, got %d", counter.inits)
+	}
+	for _, name := range []string{"FetchUser", "FetchOrder", "RenderPage"} {
+		if registry.GetAdviceCount(name) != 1 {
+			t.Fatalf("expected aspect advice on %s", name)
+		}
+	}
+
+	errNotFound := errors.New("not found")
+	fetchUser := Wrap1RE("FetchUser", func(id int) (string, error) { return "", errNotFound })
+	_, _ = fetchUser(1)
+	_, _ = fetchUser(2)
+	if counter.failures["FetchUser"] != 2 {
+		t.Fatalf("expected 2 failures recorded, got %d", counter.failures["FetchUser"])
+	}
+
+	// Unnamed advice inherit the aspect name, so the aspect can be switched off as a unit
+	_ = registry.DisableAdvice(counter.Name())
+	_, _ = fetchUser(3)
+	if counter.failures["FetchUser"] != 2 {
+		t.Fatal("expected disabled aspect to record nothing")
+	}
+
+	_ = registry.Shutdown(context.Background())
+	if counter.closes != 1 {
+		t.Fatalf("expected Close once, got %d", counter.closes)
+	}
+}
+
+func TestAspect_ApplyErrors(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Apply(On("Missing"), &failureCounter{}); err == nil {
+		t.Fatal("expected error when pointcut matches nothing")
+	}
+
+	registry.MustRegister("Func")
+	registry.Freeze()
+	if err := registry.Apply(All(), &failureCounter{}); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen, got %v", err)
+	}
+}
+
+func TestAspect_ApplyIsAllOrNothing(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Present")
+
+	advice := []Advice{{Name: "audit", Type: Before, Handler: func(ctx *Context) error { return nil }}}
+	if err := registry.addAdviceToAll([]string{"Present", "Unregistered"}, advice); err == nil {
+		t.Fatal("expected error for an unregistered function")
+	}
+	if count := registry.GetAdviceCount("Present"); count != 0 {
+		t.Fatalf("expected no advice left on 'Present', got %d", count)
+	}
+}
+
+func TestAspect_ApplyKeepsTheAspectsAdvice(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Func")
+
+	stored := &storedAdvice{advice: []Advice{{Type: Before, Handler: func(ctx *Context) error { return nil }}}}
+	registry.MustApply(On("Func"), stored)
+	if stored.advice[0].Name != "" {
+		t.Fatalf("expected the aspect's advice to stay unnamed, got %q", stored.advice[0].Name)
+	}
+}
+
+func TestAspect_FailedApplyClosesTheAspect(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Func")
+
+	stored := &storedAdvice{
+		advice: []Advice{{Type: Before, Handler: func(ctx *Context) error { return nil }}},
+		onInit: registry.Freeze, // Makes adding the advice fail after Init
+	}
+	if err := registry.Apply(On("Func"), stored); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen, got %v", err)
+	}
+	if stored.closes != 1 || len(registry.Aspects()) != 0 {
+		t.Fatalf("expected the aspect closed and forgotten, got %d closes and %d aspects", stored.closes, len(registry.Aspects()))
+	}
+}
+
+func TestAspect_NonComparableAppliedOnce(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Login")
+	registry.MustRegister("Logout")
+
+	table := sessionTable{}
+	registry.MustApply(On("Login"), table)
+	registry.MustApply(On("Logout"), table)
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	if table["inits"] != 1 || table["closes"] != 1 {
+		t.Fatalf("expected one Init and one Close, got %v", table)
+	}
+}
+
+func TestPointcut_Combinators(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("UserCreate", WithTags(map[string]string{"audit": "true"}))
+	registry.MustRegister("UserDelete", WithTags(map[string]string{"audit": "true"}))
+	registry.MustRegister("UserList")
+	registry.MustRegister("OrderCreate", WithTags(map[string]string{"audit": "true"}))
+
+	tests := []struct {
+		name     string
+		pointcut Pointcut
+		expected []string
+	}{
+		{"on", On("UserList", "Missing"), []string{"UserList"}},
+		{"matching", Matching("User*"), []string{"UserCreate", "UserDelete", "UserList"}},
+		{"tagged", Tagged("audit", "true"), []string{"OrderCreate", "UserCreate", "UserDelete"}},
+		{"and", Matching("User*").And(Tagged("audit", "true")), []string{"UserCreate", "UserDelete"}},
+		{"or", On("UserList").Or(On("OrderCreate")), []string{"OrderCreate", "UserList"}},
+		{"not", Matching("User*").Not(), []string{"OrderCreate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := registry.Select(tt.pointcut); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if tags := registry.Tags("UserCreate"); tags["audit"] != "true" {
+		t.Errorf("expected audit tag, got %v", tags)
+	}
+	if tags := registry.Tags("UserList"); tags != nil {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
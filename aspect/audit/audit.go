@@ -0,0 +1,151 @@
+// Package audit - audit provides an aspect writing an append-only audit record of every call:
+// who made it, what was called with which (redacted) arguments, and how it ended
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// Outcomes of audited calls.
+const (
+	OutcomeSuccess  = "success"  // OutcomeSuccess is a call that returned without error.
+	OutcomeError    = "error"    // OutcomeError is a call whose target returned an error.
+	OutcomeRejected = "rejected" // OutcomeRejected is a call skipped by advice with an error, e.g. an open circuit.
+	OutcomePanic    = "panic"    // OutcomePanic is a call that panicked.
+)
+
+const (
+	defaultName     = "audit"
+	defaultActorKey = "actor"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Entry is a single audit record. Sequence, PrevHash and Hash are set by chaining sinks such as FileSink.
+type Entry struct {
+	Sequence uint64    `json:"seq,omitempty"`
+	Time     time.Time `json:"time"`
+	Actor    string    `json:"actor,omitempty"`
+	Function string    `json:"function"`
+	Args     []string  `json:"args,omitempty"` // Args are the aspect.Redacted arguments formatted with %v.
+	Outcome  string    `json:"outcome"`
+	Error    string    `json:"error,omitempty"`
+	PrevHash string    `json:"prev_hash,omitempty"`
+	Hash     string    `json:"hash,omitempty"`
+}
+
+// Sink stores audit entries. Implementations must be safe for concurrent use.
+type Sink interface {
+	// Write appends an entry.
+	Write(entry Entry) error
+	// Close flushes and releases the sink.
+	Close() error
+}
+
+// Config configures an Auditor. Zero values fall back to defaults.
+type Config struct {
+	Name     string // Name of the advice, for runtime switches (default "audit").
+	Priority int    // Priority of the After advice.
+	Sink     Sink   // Sink stores the entries (required), e.g. a FileSink; the caller closes it.
+	ActorKey string // ActorKey is the Context.Metadata key of the caller's identity, set by auth advice (default "actor").
+
+	// OnError is called when the sink fails to store an entry (optional).
+	OnError func(entry Entry, err error)
+}
+
+// Auditor is an aspect auditing every call of the functions it is applied to, including panicking
+// ones such as calls whose Before advice failed.
+type Auditor struct {
+	config Config
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates an Auditor, filling unset configuration with defaults.
+func New(config Config) *Auditor {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.ActorKey == "" {
+		config.ActorKey = defaultActorKey
+	}
+	return &Auditor{config: config}
+}
+
+// Name returns the advice name.
+func (auditor *Auditor) Name() string {
+	return auditor.config.Name
+}
+
+// Advice returns the After advice writing the entry; After advice runs even when the call panics.
+func (auditor *Auditor) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     auditor.config.Name,
+			Type:     aspect.After,
+			Priority: auditor.config.Priority,
+			Handler:  auditor.after,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (auditor *Auditor) Init() error {
+	if auditor.config.Sink == nil {
+		return fmt.Errorf("auditor '%s': sink is required", auditor.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; the sink belongs to the caller, who closes it once the
+// registry is shut down (it may be shared with other auditors).
+func (auditor *Auditor) Close() error {
+	return nil
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// after writes the entry of the finished call.
+func (auditor *Auditor) after(ctx *aspect.Context) error {
+	entry := Entry{
+		Time:     time.Now().UTC(),
+		Function: ctx.FunctionName,
+		Outcome:  outcome(ctx),
+	}
+	if actor, ok := ctx.Metadata[auditor.config.ActorKey]; ok {
+		entry.Actor = fmt.Sprintf("%v", actor)
+	}
+	for _, arg := range aspect.Redacted(ctx).Args {
+		entry.Args = append(entry.Args, fmt.Sprintf("%v", arg))
+	}
+	switch {
+	case ctx.HasPanic():
+		entry.Error = fmt.Sprintf("%v", ctx.PanicValue)
+	case ctx.Error != nil:
+		entry.Error = ctx.Error.Error()
+	}
+
+	if err := auditor.config.Sink.Write(entry); err != nil && auditor.config.OnError != nil {
+		auditor.config.OnError(entry, err)
+	}
+	return nil
+}
+
+// outcome classifies how a call ended.
+func outcome(ctx *aspect.Context) string {
+	switch {
+	case ctx.HasPanic():
+		return OutcomePanic
+	case ctx.Error != nil && ctx.Skipped:
+		return OutcomeRejected
+	case ctx.Error != nil:
+		return OutcomeError
+	default:
+		return OutcomeSuccess
+	}
+}
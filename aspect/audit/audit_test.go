@@ -0,0 +1,196 @@
+// Package audit - audit_test validates audit entries, the HMAC chain and tamper detection
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// testKey is the HMAC key of the audit files of the tests.
+var testKey = []byte("test-audit-key")
+
+// setup registers a function on a fresh global registry and applies an Auditor writing to a new file.
+func setup(t *testing.T, name string) string {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := OpenFile(path, testKey, Head{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sink.Close() })
+
+	registry.MustRegister(name, aspect.WithRedactedArgs(1))
+	registry.MustAddAdvice(name, aspect.Advice{Type: aspect.Before, Priority: 100, Handler: func(ctx *aspect.Context) error {
+		ctx.Metadata["userID"] = "user_123"
+		return nil
+	}})
+	registry.MustApply(aspect.On(name), New(Config{Sink: sink, ActorKey: "userID"}))
+	return path
+}
+
+// lines returns the lines of a file.
+func lines(t *testing.T, path string) []string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(content)), "\n")
+}
+
+// writeLines replaces the content of a file.
+func writeLines(t *testing.T, path string, lines []string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rechain modifies an entry line and recomputes its hash with the test key, as a key holder
+// rewriting the end of the trail would.
+func rechain(t *testing.T, line string, modify func(entry *Entry)) string {
+	t.Helper()
+	var entry Entry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatal(err)
+	}
+	modify(&entry)
+	hash, err := hashEntry(testKey, entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry.Hash = hash
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(encoded)
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestAuditor_WritesChainedEntries(t *testing.T) {
+	path := setup(t, "ChangePassword")
+	changePassword := aspect.Wrap2E("ChangePassword", func(user, password string) error {
+		if password == "" {
+			return errors.New("empty password")
+		}
+		return nil
+	})
+
+	_ = changePassword("alice", "s3cret")
+	_ = changePassword("alice", "")
+	func() {
+		defer func() { _ = recover() }()
+		aspect.Wrap2E("ChangePassword", func(user, password string) error { panic("db down") })("bob", "x")
+	}()
+
+	head, err := VerifyFile(path, testKey, Head{})
+	if err != nil || head.Sequence != 3 {
+		t.Fatalf("expected an intact chain of 3 entries, got %+v (%v)", head, err)
+	}
+
+	written := lines(t, path)
+	if !strings.Contains(written[0], `"actor":"user_123"`) || !strings.Contains(written[0], `"args":["alice","[REDACTED]"]`) || strings.Contains(written[0], "s3cret") {
+		t.Errorf("expected actor and redacted args, got %s", written[0])
+	}
+	for index, want := range []string{OutcomeSuccess, OutcomeError, OutcomePanic} {
+		if !strings.Contains(written[index], `"outcome":"`+want+`"`) {
+			t.Errorf("expected outcome %s, got %s", want, written[index])
+		}
+	}
+
+	// Reopening continues the chain
+	sink, err := OpenFile(path, testKey, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+	if err := sink.Write(Entry{Function: "Manual", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatal(err)
+	}
+	if head, err := VerifyFile(path, testKey, head); err != nil || head != sink.Head() || head.Sequence != 4 {
+		t.Fatalf("expected the reopened chain to stay intact, got %+v (%v)", head, err)
+	}
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	path := setup(t, "Transfer")
+	transfer := aspect.Wrap2E("Transfer", func(account string, amount int) error { return nil })
+	for _, account := range []string{"a", "b", "c"} {
+		_ = transfer(account, 100)
+	}
+	original := lines(t, path)
+	expected, err := VerifyFile(path, testKey, Head{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := map[string][]string{
+		"modified":  {original[0], strings.Replace(original[1], `"b"`, `"evil"`, 1), original[2]},
+		"deleted":   {original[0], original[2]},
+		"reorder":   {original[1], original[0], original[2]},
+		"truncated": {original[0], original[1]},
+		"rewritten": {original[0], original[1], rechain(t, original[2], func(entry *Entry) { entry.Args = []string{"evil", "100"} })},
+	}
+	for name, content := range tampered {
+		writeLines(t, path, content)
+		var verificationErr *VerificationError
+		if _, err := VerifyFile(path, testKey, expected); !errors.Is(err, ErrTampered) || !errors.As(err, &verificationErr) {
+			t.Errorf("%s: expected tampering to be detected, got %v", name, err)
+		}
+		if _, err := OpenFile(path, testKey, expected); !errors.Is(err, ErrTampered) {
+			t.Errorf("%s: expected OpenFile to refuse a tampered trail, got %v", name, err)
+		}
+	}
+}
+
+func TestVerify_RequiresTheKey(t *testing.T) {
+	path := setup(t, "Transfer")
+	transfer := aspect.Wrap2E("Transfer", func(account string, amount int) error { return nil })
+	_ = transfer("a", 100)
+
+	if _, err := VerifyFile(path, []byte("other-key"), Head{}); !errors.Is(err, ErrTampered) {
+		t.Errorf("expected a trail chained with another key to be rejected, got %v", err)
+	}
+	if _, err := VerifyFile(path, nil, Head{}); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("expected verification without key to fail, got %v", err)
+	}
+	if _, err := OpenFile(path, nil, Head{}); !errors.Is(err, ErrMissingKey) {
+		t.Errorf("expected opening without key to fail, got %v", err)
+	}
+}
+
+func TestAuditor_InitRequiresSink(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Fatal("expected error without sink")
+	}
+}
+
+func TestAuditor_CloseLeavesTheSinkOpen(t *testing.T) {
+	sink, err := OpenFile(filepath.Join(t.TempDir(), "audit.log"), testKey, Head{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sink.Close()
+
+	if err := New(Config{Sink: sink}).Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(Entry{Function: "Manual", Outcome: OutcomeSuccess}); err != nil {
+		t.Fatalf("expected the caller's sink to stay open, got %v", err)
+	}
+}
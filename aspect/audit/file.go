@@ -0,0 +1,198 @@
+// Package audit - file provides an HMAC-chained JSON-lines audit sink and its verification
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ErrTampered is matched (via errors.Is) by verification errors of modified, inserted or deleted entries.
+var ErrTampered = errors.New("audit trail tampered")
+
+// ErrMissingKey is returned by OpenFile and Verify without an HMAC key.
+var ErrMissingKey = errors.New("audit key is required")
+
+// -------------------------------------------- Types --------------------------------------------
+
+// FileSink appends entries to a file as JSON lines, each carrying the HMAC of the previous one,
+// so that modifying or deleting an entry breaks the chain, and rewriting the chain requires the key
+// (see Verify).
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	key  []byte
+	head Head
+}
+
+// Head identifies the last entry of a chain. Store it elsewhere (e.g. periodically) and pass it to
+// Verify to also detect entries deleted from the end of the file.
+type Head struct {
+	Sequence uint64 // Sequence is the number of entries.
+	Hash     string // Hash is the HMAC of the last entry (empty for an empty chain).
+}
+
+// VerificationError describes the first broken link of a chain.
+type VerificationError struct {
+	Line   int    // Line is the 1-based line of the offending entry.
+	Reason string // Reason describes the inconsistency.
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// OpenFile opens or creates an audit file chained with an HMAC-SHA256 key, verifying its existing
+// entries against the expected head to continue their chain. The zero Head accepts any intact
+// chain, so pass the last stored Head to also catch entries deleted from the end.
+func OpenFile(path string, key []byte, expected Head) (*FileSink, error) {
+	if len(key) == 0 {
+		return nil, fmt.Errorf("audit file '%s': %w", path, ErrMissingKey)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := Verify(file, key, expected)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("audit file '%s': %w", path, err)
+	}
+	return &FileSink{file: file, key: bytes.Clone(key), head: head}, nil
+}
+
+// Write implements Sink; the entry is chained, appended and synced to disk. A failed write is
+// truncated away so that a partial line does not break the chain for later entries.
+func (sink *FileSink) Write(entry Entry) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+
+	entry.Sequence = sink.head.Sequence + 1
+	entry.PrevHash = sink.head.Hash
+	hash, err := hashEntry(sink.key, entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	offset, err := sink.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := sink.file.Write(append(line, '\n')); err != nil {
+		return errors.Join(err, sink.file.Truncate(offset))
+	}
+	if err := sink.file.Sync(); err != nil {
+		return errors.Join(err, sink.file.Truncate(offset))
+	}
+	sink.head = Head{Sequence: entry.Sequence, Hash: entry.Hash}
+	return nil
+}
+
+// Head returns the last entry written.
+func (sink *FileSink) Head() Head {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return sink.head
+}
+
+// Close implements Sink.
+func (sink *FileSink) Close() error {
+	return sink.file.Close()
+}
+
+// Verify reads a trail chained with key and returns its head, or a *VerificationError matching
+// ErrTampered at the first entry that was modified, inserted, reordered or follows a deleted one.
+// The trail must contain the expected head, a previously stored Head: this catches entries deleted
+// from the end and chains rewritten from an earlier entry. The zero Head accepts any intact chain.
+func Verify(reader io.Reader, key []byte, expected Head) (Head, error) {
+	var head Head
+	if len(key) == 0 {
+		return head, ErrMissingKey
+	}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+
+	for line := 1; scanner.Scan(); line++ {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return head, &VerificationError{Line: line, Reason: fmt.Sprintf("malformed entry: %v", err)}
+		}
+		if entry.Sequence != head.Sequence+1 {
+			return head, &VerificationError{Line: line, Reason: fmt.Sprintf("sequence %d follows %d", entry.Sequence, head.Sequence)}
+		}
+		if entry.PrevHash != head.Hash {
+			return head, &VerificationError{Line: line, Reason: "previous hash does not match the preceding entry"}
+		}
+
+		hash, err := hashEntry(key, entry)
+		if err != nil {
+			return head, err
+		}
+		if !hmac.Equal([]byte(entry.Hash), []byte(hash)) {
+			return head, &VerificationError{Line: line, Reason: "hash does not match the entry content"}
+		}
+		if entry.Sequence == expected.Sequence && entry.Hash != expected.Hash {
+			return head, &VerificationError{Line: line, Reason: "hash does not match the expected head"}
+		}
+		head = Head{Sequence: entry.Sequence, Hash: entry.Hash}
+	}
+	if err := scanner.Err(); err != nil {
+		return head, err
+	}
+
+	if head.Sequence < expected.Sequence {
+		return head, &VerificationError{
+			Line:   int(head.Sequence) + 1,
+			Reason: fmt.Sprintf("trail ends at entry %d before the expected head %d", head.Sequence, expected.Sequence),
+		}
+	}
+	return head, nil
+}
+
+// VerifyFile verifies the trail stored in a file, see Verify.
+func VerifyFile(path string, key []byte, expected Head) (Head, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Head{}, err
+	}
+	defer file.Close()
+	return Verify(file, key, expected)
+}
+
+// Error implements the error interface.
+func (err *VerificationError) Error() string {
+	return fmt.Sprintf("%v at line %d: %s", ErrTampered, err.Line, err.Reason)
+}
+
+// Unwrap makes VerificationError match ErrTampered.
+func (err *VerificationError) Unwrap() error {
+	return ErrTampered
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// hashEntry returns the hex HMAC-SHA256 of the entry's JSON encoding without its own hash.
+func hashEntry(key []byte, entry Entry) (string, error) {
+	entry.Hash = ""
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(encoded)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,291 @@
+// Package bulkhead - bulkhead provides an aspect limiting concurrent executions per function or key,
+// with a bounded wait queue
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	defaultName        = "bulkhead"
+	defaultIdleTimeout = 10 * time.Minute
+	minSweepSize       = 64
+)
+
+var (
+	// ErrBulkheadFull is matched (via errors.Is) by errors of calls rejected because every slot and queue position was taken.
+	ErrBulkheadFull = errors.New("bulkhead is full")
+	// ErrQueueTimeout is matched (via errors.Is) by errors of calls that waited in the queue longer than QueueTimeout.
+	ErrQueueTimeout = errors.New("bulkhead queue timeout")
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Config configures a Bulkhead. Zero values fall back to defaults.
+type Config struct {
+	Name          string        // Name of the advice and prefix of its gauges (default "bulkhead").
+	Priority      int           // Priority of the Around advice.
+	MaxConcurrent int           // MaxConcurrent is the number of calls allowed to run at once per key (required).
+	MaxQueue      int           // MaxQueue is the number of calls allowed to wait for a slot per key (default 0, reject at once).
+	QueueTimeout  time.Duration // QueueTimeout bounds the time spent queued (default: until the call's context is done).
+
+	// IdleTimeout forgets the compartment of a key unused for that long, with its gauges, bounding
+	// memory when keys are per tenant or per argument (default 10m).
+	IdleTimeout time.Duration
+
+	// KeyFunc derives the compartment of a call (default: the function name, one compartment per function).
+	KeyFunc func(ctx *aspect.Context) string
+
+	// Clock provides the time of idle tracking (default: the system clock).
+	Clock Clock
+}
+
+// Clock provides the current time; inject a fake in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// Bulkhead is an aspect holding one compartment of concurrency slots per key.
+// Its "<name>.active" and "<name>.queued" gauges are published, per key, in every registry it is applied to.
+type Bulkhead struct {
+	config Config
+
+	mu           sync.Mutex
+	compartments map[string]*compartment
+	registries   []*aspect.Registry
+	sweepSize    int // sweepSize is the number of compartments that triggers the next sweep of idle ones.
+}
+
+// compartment bounds the concurrency of a single key.
+type compartment struct {
+	slots  chan struct{}
+	active atomic.Int64
+	queued atomic.Int64
+
+	users    int       // users are the calls holding the compartment, guarded by Bulkhead.mu.
+	lastUsed time.Time // lastUsed is the time the last call released it, guarded by Bulkhead.mu.
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Bulkhead, filling unset configuration with defaults.
+func New(config Config) *Bulkhead {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.MaxQueue < 0 {
+		config.MaxQueue = 0
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultIdleTimeout
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(ctx *aspect.Context) string { return ctx.FunctionName }
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &Bulkhead{config: config, compartments: make(map[string]*compartment), sweepSize: minSweepSize}
+}
+
+// Name returns the advice name.
+func (bulkhead *Bulkhead) Name() string {
+	return bulkhead.config.Name
+}
+
+// Advice returns the Around advice bounding concurrency.
+func (bulkhead *Bulkhead) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     bulkhead.config.Name,
+			Type:     aspect.Around,
+			Priority: bulkhead.config.Priority,
+			Handler:  bulkhead.around,
+		},
+	}
+}
+
+// Attach implements aspect.RegistryAware; gauges are published in every attached registry.
+func (bulkhead *Bulkhead) Attach(registry *aspect.Registry) {
+	bulkhead.mu.Lock()
+	defer bulkhead.mu.Unlock()
+
+	bulkhead.registries = append(bulkhead.registries, registry)
+	for key, compartment := range bulkhead.compartments {
+		bulkhead.publish(registry, key, compartment)
+	}
+}
+
+// Init validates the configuration.
+func (bulkhead *Bulkhead) Init() error {
+	if bulkhead.config.MaxConcurrent <= 0 {
+		return fmt.Errorf("bulkhead '%s': max concurrent must be positive", bulkhead.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; it removes the published gauges.
+func (bulkhead *Bulkhead) Close() error {
+	bulkhead.mu.Lock()
+	defer bulkhead.mu.Unlock()
+
+	for key := range bulkhead.compartments {
+		bulkhead.unpublish(key)
+	}
+	return nil
+}
+
+// Active returns the number of calls running in the compartment of key (0 if it has not been used).
+func (bulkhead *Bulkhead) Active(key string) int64 {
+	if compartment, exists := bulkhead.lookup(key); exists {
+		return compartment.active.Load()
+	}
+	return 0
+}
+
+// Queued returns the number of calls waiting in the compartment of key (0 if it has not been used).
+func (bulkhead *Bulkhead) Queued(key string) int64 {
+	if compartment, exists := bulkhead.lookup(key); exists {
+		return compartment.queued.Load()
+	}
+	return 0
+}
+
+// Now implements Clock.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around runs the call in a free slot, queues it, or rejects it.
+func (bulkhead *Bulkhead) around(ctx *aspect.Context) error {
+	key := bulkhead.config.KeyFunc(ctx)
+	compartment := bulkhead.compartment(key)
+	defer bulkhead.release(compartment)
+
+	if err := bulkhead.acquire(ctx.Context(), key, compartment); err != nil {
+		ctx.Error = err
+		ctx.Skipped = true
+		return nil
+	}
+	defer func() {
+		compartment.active.Add(-1)
+		<-compartment.slots
+	}()
+
+	_ = ctx.Proceed()
+	return nil
+}
+
+// acquire takes a slot, waiting in the queue if allowed.
+func (bulkhead *Bulkhead) acquire(ctx context.Context, key string, compartment *compartment) error {
+	select {
+	case compartment.slots <- struct{}{}:
+		compartment.active.Add(1)
+		return nil
+	default:
+	}
+
+	if compartment.queued.Add(1) > int64(bulkhead.config.MaxQueue) {
+		compartment.queued.Add(-1)
+		return fmt.Errorf("bulkhead '%s' for '%s': %w", bulkhead.config.Name, key, ErrBulkheadFull)
+	}
+	defer compartment.queued.Add(-1)
+
+	var timeout <-chan time.Time
+	if bulkhead.config.QueueTimeout > 0 {
+		timer := time.NewTimer(bulkhead.config.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case compartment.slots <- struct{}{}:
+		compartment.active.Add(1)
+		return nil
+	case <-timeout:
+		return fmt.Errorf("bulkhead '%s' for '%s' after %v: %w", bulkhead.config.Name, key, bulkhead.config.QueueTimeout, ErrQueueTimeout)
+	case <-ctx.Done():
+		return fmt.Errorf("bulkhead '%s' for '%s' while queued: %w", bulkhead.config.Name, key, context.Cause(ctx))
+	}
+}
+
+// compartment returns the compartment of key for a call, creating and publishing it on first use
+// and sweeping idle compartments whenever their number has doubled since the last sweep.
+// The call hands it back with release.
+func (bulkhead *Bulkhead) compartment(key string) *compartment {
+	bulkhead.mu.Lock()
+	defer bulkhead.mu.Unlock()
+
+	existing, exists := bulkhead.compartments[key]
+	if !exists {
+		if len(bulkhead.compartments) >= bulkhead.sweepSize {
+			bulkhead.sweep(bulkhead.config.Clock.Now())
+		}
+		existing = &compartment{slots: make(chan struct{}, max(bulkhead.config.MaxConcurrent, 1))}
+		bulkhead.compartments[key] = existing
+		for _, registry := range bulkhead.registries {
+			bulkhead.publish(registry, key, existing)
+		}
+	}
+	existing.users++
+	return existing
+}
+
+// release hands back a compartment taken by compartment.
+func (bulkhead *Bulkhead) release(compartment *compartment) {
+	now := bulkhead.config.Clock.Now()
+	bulkhead.mu.Lock()
+	defer bulkhead.mu.Unlock()
+
+	compartment.users--
+	compartment.lastUsed = now
+}
+
+// sweep forgets compartments without calls that were released longer than IdleTimeout ago, with
+// their gauges. Caller holds bulkhead.mu.
+func (bulkhead *Bulkhead) sweep(now time.Time) {
+	for key, existing := range bulkhead.compartments {
+		if existing.users == 0 && now.Sub(existing.lastUsed) > bulkhead.config.IdleTimeout {
+			delete(bulkhead.compartments, key)
+			bulkhead.unpublish(key)
+		}
+	}
+	bulkhead.sweepSize = max(2*len(bulkhead.compartments), minSweepSize)
+}
+
+// lookup returns the compartment of key without creating it.
+func (bulkhead *Bulkhead) lookup(key string) (*compartment, bool) {
+	bulkhead.mu.Lock()
+	defer bulkhead.mu.Unlock()
+
+	existing, exists := bulkhead.compartments[key]
+	return existing, exists
+}
+
+// unpublish removes the gauges of a compartment from every attached registry. Caller holds bulkhead.mu.
+func (bulkhead *Bulkhead) unpublish(key string) {
+	for _, registry := range bulkhead.registries {
+		registry.UnregisterGauge(bulkhead.config.Name+".active", key)
+		registry.UnregisterGauge(bulkhead.config.Name+".queued", key)
+	}
+}
+
+// publish registers the gauges of a compartment. Caller holds bulkhead.mu.
+func (bulkhead *Bulkhead) publish(registry *aspect.Registry, key string, compartment *compartment) {
+	_ = registry.RegisterGauge(aspect.Gauge{Name: bulkhead.config.Name + ".active", Key: key, Value: compartment.active.Load})
+	_ = registry.RegisterGauge(aspect.Gauge{Name: bulkhead.config.Name + ".queued", Key: key, Value: compartment.queued.Load})
+}
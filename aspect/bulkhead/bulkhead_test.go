@@ -0,0 +1,191 @@
+// Package bulkhead - bulkhead_test validates concurrency limits, queueing and gauges
+package bulkhead
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// waitFor polls condition until it holds or the test times out.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+// setup registers functions on a fresh global registry and applies the bulkhead to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestBulkhead_LimitsConcurrencyAndQueue(t *testing.T) {
+	bulkhead := New(Config{MaxConcurrent: 2, MaxQueue: 1})
+	registry := setup(t, bulkhead, "QueryDB")
+
+	release := make(chan struct{})
+	queryDB := aspect.Wrap0RE("QueryDB", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = queryDB()
+		}(i)
+	}
+	waitFor(t, func() bool { return bulkhead.Active("QueryDB") == 2 && bulkhead.Queued("QueryDB") == 1 })
+
+	// Gauges are live through the registry
+	if active, _ := registry.GaugeValue("bulkhead.active", "QueryDB"); active != 2 {
+		t.Errorf("expected active gauge 2, got %d", active)
+	}
+	if queued, _ := registry.GaugeValue("bulkhead.queued", "QueryDB"); queued != 1 {
+		t.Errorf("expected queued gauge 1, got %d", queued)
+	}
+
+	// Every slot and queue position is taken
+	if _, err := queryDB(); !errors.Is(err, ErrBulkheadFull) {
+		t.Fatalf("expected ErrBulkheadFull, got %v", err)
+	}
+
+	close(release)
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("call %d: expected success, got %v", i, err)
+		}
+	}
+	if bulkhead.Active("QueryDB") != 0 || bulkhead.Queued("QueryDB") != 0 {
+		t.Fatalf("expected empty compartment, got active=%d queued=%d", bulkhead.Active("QueryDB"), bulkhead.Queued("QueryDB"))
+	}
+}
+
+func TestBulkhead_ReadingUnknownKeyPublishesNothing(t *testing.T) {
+	bulkhead := New(Config{MaxConcurrent: 1})
+	registry := setup(t, bulkhead, "Report")
+
+	if bulkhead.Active("tenant-42") != 0 || bulkhead.Queued("tenant-42") != 0 {
+		t.Fatal("expected an unused key to read as empty")
+	}
+	if gauges := registry.Gauges(); len(gauges) != 0 {
+		t.Fatalf("expected no gauges for unused keys, got %v", gauges)
+	}
+}
+
+func TestBulkhead_ForgetsIdleKeys(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	bulkhead := New(Config{
+		MaxConcurrent: 1,
+		IdleTimeout:   time.Minute,
+		KeyFunc:       func(ctx *aspect.Context) string { return fmt.Sprint(ctx.Args[0]) },
+		Clock:         clock,
+	})
+	registry := setup(t, bulkhead, "Lookup")
+	lookup := aspect.Wrap1("Lookup", func(id int) {})
+
+	for id := range minSweepSize {
+		lookup(id)
+	}
+	clock.Advance(2 * time.Minute)
+	lookup(0)
+
+	// The next new key finds minSweepSize compartments and sweeps all but the one just used
+	lookup(minSweepSize)
+	if gauges := registry.Gauges(); len(gauges) != 4 {
+		t.Fatalf("expected the gauges of 2 compartments, got %d gauges", len(gauges))
+	}
+	if _, exists := bulkhead.lookup("1"); exists {
+		t.Fatal("expected the idle compartment to be forgotten")
+	}
+}
+
+func TestBulkhead_QueueTimeout(t *testing.T) {
+	bulkhead := New(Config{MaxConcurrent: 1, MaxQueue: 1, QueueTimeout: 10 * time.Millisecond})
+	setup(t, bulkhead, "Export")
+
+	release := make(chan struct{})
+	export := aspect.Wrap0RE("Export", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = export()
+		close(done)
+	}()
+	waitFor(t, func() bool { return bulkhead.Active("Export") == 1 })
+
+	if _, err := export(); !errors.Is(err, ErrQueueTimeout) {
+		t.Fatalf("expected ErrQueueTimeout, got %v", err)
+	}
+	close(release)
+	<-done
+}
+
+func TestBulkhead_PanicReleasesSlot(t *testing.T) {
+	bulkhead := New(Config{MaxConcurrent: 1})
+	setup(t, bulkhead, "Crash")
+
+	crash := aspect.Wrap0("Crash", func() { panic("boom") })
+	func() {
+		defer func() { _ = recover() }()
+		crash()
+	}()
+
+	if bulkhead.Active("Crash") != 0 {
+		t.Fatalf("expected slot released after panic, got %d active", bulkhead.Active("Crash"))
+	}
+}
+
+func TestBulkhead_InitRequiresLimit(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Fatal("expected error without MaxConcurrent")
+	}
+}
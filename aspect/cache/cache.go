@@ -0,0 +1,389 @@
+// Package cache - cache provides an aspect memoizing wrapped function results with TTL,
+// negative caching and stale-while-revalidate
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	defaultName       = "cache"
+	defaultMaxEntries = 10_000
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// KeyFunc derives the cache key of a call from its context (typically its arguments).
+type KeyFunc func(ctx *aspect.Context) string
+
+// Clock provides the current time; inject a fake in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// Stats are the cache counters of a single function.
+type Stats struct {
+	Hits      uint64 // Hits are calls served from a fresh entry.
+	StaleHits uint64 // StaleHits are calls served from a stale entry while it was revalidated.
+	Misses    uint64 // Misses are calls that ran the wrapped function.
+	Refreshes uint64 // Refreshes are completed background revalidations.
+}
+
+// Config configures a Cache. Zero values fall back to defaults.
+type Config struct {
+	Name     string // Name of the advice, for runtime switches (default "cache").
+	Priority int    // Priority of the Around advice.
+
+	Store      Store    // Store holds the entries (default NewMemoryStore(MaxEntries, Eviction)).
+	MaxEntries int      // MaxEntries bounds the default store (default 10000, negative for unbounded).
+	Eviction   Eviction // Eviction is the policy of the default store (default LRU).
+
+	TTL                  time.Duration // TTL is the time results stay fresh (default: forever).
+	ErrorTTL             time.Duration // ErrorTTL caches errors for that long (default: errors are not cached).
+	StaleWhileRevalidate time.Duration // StaleWhileRevalidate serves expired entries for that long while refreshing them in the background.
+
+	// KeyFunc derives the key of a call (default Args(), every argument except a context.Context).
+	KeyFunc KeyFunc
+	// Clock provides the time (default: the system clock).
+	Clock Clock
+}
+
+// Cache is an aspect memoizing the results of the functions it is applied to.
+type Cache struct {
+	config Config
+
+	mu         sync.Mutex
+	functions  map[string]*functionState
+	refreshing map[string]struct{}
+}
+
+// functionState holds the live Stats of a function and the generation scoping its keys.
+type functionState struct {
+	hits, staleHits, misses, refreshes atomic.Uint64
+	generation                         atomic.Uint64 // generation changes on InvalidateAll, orphaning older entries.
+	version                            atomic.Uint64 // version changes whenever entries are invalidated or put, see put.
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Cache, filling unset configuration with defaults.
+func New(config Config) *Cache {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.MaxEntries == 0 {
+		config.MaxEntries = defaultMaxEntries
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryStore(config.MaxEntries, config.Eviction)
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = Args()
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &Cache{
+		config:     config,
+		functions:  make(map[string]*functionState),
+		refreshing: make(map[string]struct{}),
+	}
+}
+
+// Name returns the advice name.
+func (cache *Cache) Name() string {
+	return cache.config.Name
+}
+
+// Advice returns the Around advice serving cached results.
+func (cache *Cache) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     cache.config.Name,
+			Type:     aspect.Around,
+			Priority: cache.config.Priority,
+			Handler:  cache.around,
+		},
+	}
+}
+
+// Init implements aspect.Aspect; entries are stored lazily.
+func (cache *Cache) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; it clears the store.
+func (cache *Cache) Close() error {
+	cache.Clear()
+	return nil
+}
+
+// Stats returns the counters of a function (zero if it was never called).
+func (cache *Cache) Stats(functionName string) Stats {
+	state, exists := cache.lookup(functionName)
+	if !exists {
+		return Stats{}
+	}
+	return Stats{
+		Hits:      state.hits.Load(),
+		StaleHits: state.staleHits.Load(),
+		Misses:    state.misses.Load(),
+		Refreshes: state.refreshes.Load(),
+	}
+}
+
+// Invalidate removes the entry cached for a function under key (as returned by the KeyFunc).
+// Misses of the function already running do not store their possibly outdated results.
+func (cache *Cache) Invalidate(functionName, key string) {
+	state, exists := cache.lookup(functionName)
+	if !exists {
+		return // Nothing was cached for the function
+	}
+	state.version.Add(1)
+	cache.config.Store.Delete(storeKey(functionName, state, key))
+}
+
+// InvalidateAll makes every entry cached for a function unreachable. Stores implementing
+// PrefixDeleter (such as MemoryStore) drop the orphaned entries at once; others leave them
+// to their own expiry or eviction. As with Invalidate, misses already running are not stored.
+func (cache *Cache) InvalidateAll(functionName string) {
+	state, exists := cache.lookup(functionName)
+	if !exists {
+		return
+	}
+	state.version.Add(1)
+	generation := state.generation.Add(1) - 1
+	if deleter, ok := cache.config.Store.(PrefixDeleter); ok {
+		deleter.DeletePrefix(generationPrefix(functionName, generation))
+	}
+}
+
+// Put stores results as the fresh entry of a function under key, as if a call had returned them.
+func (cache *Cache) Put(functionName, key string, results ...any) {
+	state := cache.function(functionName)
+	state.version.Add(1)
+	cache.store(storeKey(functionName, state, key), results, nil, cache.config.TTL)
+}
+
+// Clear removes all cached entries.
+func (cache *Cache) Clear() {
+	cache.mu.Lock()
+	for _, state := range cache.functions {
+		state.version.Add(1)
+	}
+	cache.mu.Unlock()
+	cache.config.Store.Clear()
+}
+
+// HitRatio returns the share of calls served from the cache, stale hits included.
+func (stats Stats) HitRatio() float64 {
+	total := stats.Hits + stats.StaleHits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits+stats.StaleHits) / float64(total)
+}
+
+// Args derives keys from the arguments at the given indices (every argument except a
+// context.Context when none are given).
+func Args(indices ...int) KeyFunc {
+	return func(ctx *aspect.Context) string {
+		if len(indices) == 0 {
+			values := make([]any, 0, len(ctx.Args))
+			for _, arg := range ctx.Args {
+				if _, isContext := arg.(context.Context); !isContext {
+					values = append(values, arg)
+				}
+			}
+			return Key(values...)
+		}
+
+		values := make([]any, len(indices))
+		for i, index := range indices {
+			if index < len(ctx.Args) {
+				values[i] = ctx.Args[index]
+			}
+		}
+		return Key(values...)
+	}
+}
+
+// Key formats values into a cache key the same way Args does, for keys built outside a call.
+func Key(values ...any) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprintf("%#v", value)
+	}
+	return strings.Join(parts, "|")
+}
+
+// Now returns the system time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around serves fresh entries, serves and revalidates stale ones, and stores the outcome of misses.
+func (cache *Cache) around(ctx *aspect.Context) error {
+	state := cache.function(ctx.FunctionName)
+	key := storeKey(ctx.FunctionName, state, cache.config.KeyFunc(ctx))
+	now := cache.config.Clock.Now()
+
+	if entry, found := cache.config.Store.Get(key); found {
+		switch {
+		case entry.ExpiresAt.IsZero() || now.Before(entry.ExpiresAt):
+			state.hits.Add(1)
+			serve(ctx, entry)
+			return nil
+		case now.Before(entry.ExpiresAt.Add(cache.config.StaleWhileRevalidate)):
+			state.staleHits.Add(1)
+			cache.revalidate(ctx.Fork(), key, state)
+			serve(ctx, entry)
+			return nil
+		}
+		cache.config.Store.Delete(key)
+	}
+
+	state.misses.Add(1)
+	version := state.version.Load()
+	_ = ctx.Proceed()
+	cache.put(key, ctx, state, version)
+	return nil
+}
+
+// revalidate refreshes a stale entry in the background, once per key at a time.
+// No refresh starts once the registry is shutting down.
+func (cache *Cache) revalidate(fork *aspect.Context, key string, state *functionState) {
+	cache.mu.Lock()
+	if _, busy := cache.refreshing[key]; busy {
+		cache.mu.Unlock()
+		return
+	}
+	cache.refreshing[key] = struct{}{}
+	cache.mu.Unlock()
+
+	// The refresh counts as in flight so Shutdown does not close the cache under it
+	release, err := fork.Hold()
+	if err != nil {
+		cache.mu.Lock()
+		delete(cache.refreshing, key)
+		cache.mu.Unlock()
+		return
+	}
+
+	// The caller's context.Context ends with its request, not with the refresh
+	if len(fork.Args) > 0 {
+		if parent, ok := fork.Args[0].(context.Context); ok && parent != nil {
+			fork.Args[0] = context.WithoutCancel(parent)
+		}
+	}
+	fork.Results, fork.Error, fork.Skipped = nil, nil, false
+	version := state.version.Load()
+
+	go func() {
+		defer func() {
+			// A panicking refresh keeps the stale entry
+			_ = recover()
+			cache.mu.Lock()
+			delete(cache.refreshing, key)
+			cache.mu.Unlock()
+			release()
+		}()
+
+		_ = fork.Proceed()
+		cache.put(key, fork, state, version)
+		state.refreshes.Add(1)
+	}()
+}
+
+// put stores the outcome of a call that ran the wrapped function, unless the function's entries
+// were invalidated or put since version was read before the call: its results may predate them.
+func (cache *Cache) put(key string, ctx *aspect.Context, state *functionState, version uint64) {
+	if ctx.Skipped {
+		return // Results were not produced by the function (e.g. rejected by other advice)
+	}
+	if state.version.Load() != version {
+		return
+	}
+
+	ttl := cache.config.TTL
+	if ctx.Error != nil {
+		if cache.config.ErrorTTL <= 0 {
+			return
+		}
+		ttl = cache.config.ErrorTTL
+	}
+	cache.store(key, ctx.Results, ctx.Error, ttl)
+
+	// An invalidation racing with the store may have run before the entry was set
+	if state.version.Load() != version {
+		cache.config.Store.Delete(key)
+	}
+}
+
+// store saves a copy of results and err under key, expiring after ttl (never if ttl <= 0).
+func (cache *Cache) store(key string, results []any, err error, ttl time.Duration) {
+	now := cache.config.Clock.Now()
+	entry := Entry{
+		Results:  append([]any(nil), results...),
+		Err:      err,
+		StoredAt: now,
+	}
+	if ttl > 0 {
+		entry.ExpiresAt = now.Add(ttl)
+		entry.DeleteAt = entry.ExpiresAt.Add(cache.config.StaleWhileRevalidate)
+	}
+	cache.config.Store.Set(key, entry)
+}
+
+// function returns the state of a function, creating it on first use.
+func (cache *Cache) function(functionName string) *functionState {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	state, exists := cache.functions[functionName]
+	if !exists {
+		state = &functionState{}
+		cache.functions[functionName] = state
+	}
+	return state
+}
+
+// lookup returns the state of a function without creating it.
+func (cache *Cache) lookup(functionName string) (*functionState, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	state, exists := cache.functions[functionName]
+	return state, exists
+}
+
+// storeKey scopes a call key to its function and current generation so one store can serve several functions.
+func storeKey(functionName string, state *functionState, key string) string {
+	return generationPrefix(functionName, state.generation.Load()) + key
+}
+
+// generationPrefix is the store key prefix shared by all entries of one generation of a function.
+func generationPrefix(functionName string, generation uint64) string {
+	return fmt.Sprintf("%s#%d:", functionName, generation)
+}
+
+// serve answers the call from a cached entry without running the function.
+func serve(ctx *aspect.Context, entry Entry) {
+	ctx.Results = append([]any(nil), entry.Results...)
+	ctx.Error = entry.Err
+	ctx.Skipped = true
+}
@@ -0,0 +1,349 @@
+// Package cache - cache_test validates memoization, expiry, eviction and revalidation
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+var errNotFound = errors.New("not found")
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+}
+
+// setup registers functions on a fresh global registry and applies the cache to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestCache_MemoizesWithTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cache := New(Config{TTL: 5 * time.Second, Clock: clock})
+	setup(t, cache, "FetchUserProfile")
+
+	calls := 0
+	fetch := aspect.Wrap1RE("FetchUserProfile", func(userID string) (string, error) {
+		calls++
+		return "User " + userID, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if profile, err := fetch("u1"); err != nil || profile != "User u1" {
+			t.Fatalf("expected (User u1, nil), got (%q, %v)", profile, err)
+		}
+	}
+	_, _ = fetch("u2")
+	if calls != 2 {
+		t.Fatalf("expected one call per key, got %d", calls)
+	}
+
+	clock.Advance(5 * time.Second)
+	_, _ = fetch("u1")
+	if calls != 3 {
+		t.Fatalf("expected expired entry to be recomputed, got %d calls", calls)
+	}
+
+	stats := cache.Stats("FetchUserProfile")
+	if stats.Hits != 2 || stats.Misses != 3 {
+		t.Fatalf("expected 2 hits and 3 misses, got %+v", stats)
+	}
+	if ratio := stats.HitRatio(); ratio != 0.4 {
+		t.Errorf("expected hit ratio 0.4, got %v", ratio)
+	}
+}
+
+func TestCache_NegativeCaching(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	setup(t, New(Config{ErrorTTL: time.Second, Clock: clock}), "Lookup")
+
+	calls := 0
+	lookup := aspect.Wrap1RE("Lookup", func(id int) (string, error) {
+		calls++
+		return "", errNotFound
+	})
+
+	_, _ = lookup(1)
+	if _, err := lookup(1); !errors.Is(err, errNotFound) || calls != 1 {
+		t.Fatalf("expected cached error after 1 call, got %v after %d calls", err, calls)
+	}
+
+	clock.Advance(time.Second)
+	_, _ = lookup(1)
+	if calls != 2 {
+		t.Fatalf("expected error to expire after ErrorTTL, got %d calls", calls)
+	}
+}
+
+func TestCache_ErrorsNotCachedByDefault(t *testing.T) {
+	setup(t, New(Config{}), "Flaky")
+
+	calls := 0
+	flaky := aspect.Wrap0RE("Flaky", func() (int, error) {
+		calls++
+		if calls == 1 {
+			return 0, errNotFound
+		}
+		return 42, nil
+	})
+
+	_, _ = flaky()
+	if result, err := flaky(); err != nil || result != 42 {
+		t.Fatalf("expected error not to be cached, got (%d, %v)", result, err)
+	}
+}
+
+func TestCache_StaleWhileRevalidate(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cache := New(Config{TTL: time.Second, StaleWhileRevalidate: time.Minute, Clock: clock})
+	setup(t, cache, "Recommendations")
+
+	var mu sync.Mutex
+	version := 0
+	refreshed := make(chan struct{}, 1)
+	recommendations := aspect.Wrap1RE("Recommendations", func(userID string) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		version++
+		if version > 1 {
+			refreshed <- struct{}{}
+		}
+		return version, nil
+	})
+
+	_, _ = recommendations("u1")
+	clock.Advance(2 * time.Second)
+
+	// Stale value is served immediately while the refresh runs in the background
+	if result, _ := recommendations("u1"); result != 1 {
+		t.Fatalf("expected stale value 1, got %d", result)
+	}
+	<-refreshed
+
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats("Recommendations").Refreshes == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if result, _ := recommendations("u1"); result != 2 {
+		t.Fatalf("expected refreshed value 2, got %d", result)
+	}
+
+	stats := cache.Stats("Recommendations")
+	if stats.StaleHits != 1 || stats.Refreshes != 1 || stats.Hits != 1 {
+		t.Fatalf("expected 1 stale hit, 1 refresh and 1 hit, got %+v", stats)
+	}
+}
+
+func TestCache_ShutdownWaitsForRefresh(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cache := New(Config{TTL: time.Second, StaleWhileRevalidate: time.Minute, Clock: clock})
+	registry := setup(t, cache, "Prices")
+
+	calls := 0
+	refreshing, release := make(chan struct{}), make(chan struct{})
+	prices := aspect.Wrap1R("Prices", func(sku string) int {
+		calls++
+		if calls > 1 {
+			close(refreshing)
+			<-release
+		}
+		return calls
+	})
+
+	prices("sku-1")
+	clock.Advance(2 * time.Second)
+	prices("sku-1")
+	<-refreshing
+
+	shutdown := make(chan error)
+	go func() { shutdown <- registry.Shutdown(context.Background()) }()
+	select {
+	case <-shutdown:
+		t.Fatal("expected Shutdown to wait for the running refresh")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestCache_KeysAndInvalidate(t *testing.T) {
+	cache := New(Config{KeyFunc: Args(0)})
+	setup(t, cache, "Search")
+
+	calls := 0
+	search := aspect.Wrap2RE("Search", func(query string, page int) (int, error) {
+		calls++
+		return calls, nil
+	})
+
+	_, _ = search("go", 1)
+	_, _ = search("go", 2) // Same key: only the query is part of it
+	if calls != 1 {
+		t.Fatalf("expected page to be ignored by the key, got %d calls", calls)
+	}
+
+	cache.Invalidate("Search", Key("go"))
+	_, _ = search("go", 1)
+	if calls != 2 {
+		t.Fatalf("expected invalidated entry to be recomputed, got %d calls", calls)
+	}
+}
+
+func TestCache_InvalidateDuringLoadIsNotOverwritten(t *testing.T) {
+	for name, invalidate := range map[string]func(cache *Cache){
+		"Invalidate":    func(cache *Cache) { cache.Invalidate("FetchUserProfile", Key("u1")) },
+		"InvalidateAll": func(cache *Cache) { cache.InvalidateAll("FetchUserProfile") },
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache := New(Config{})
+			setup(t, cache, "FetchUserProfile")
+
+			loading, release := make(chan struct{}), make(chan struct{})
+			names := map[string]string{"u1": "Ada"}
+			var mu sync.Mutex
+			fetch := aspect.Wrap1RE("FetchUserProfile", func(userID string) (string, error) {
+				mu.Lock()
+				name := names[userID]
+				mu.Unlock()
+				if name == "Ada" {
+					close(loading)
+					<-release // The loader read the old name and is still running
+				}
+				return name, nil
+			})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_, _ = fetch("u1")
+			}()
+			<-loading
+			mu.Lock()
+			names["u1"] = "Grace"
+			mu.Unlock()
+			invalidate(cache)
+			close(release)
+			<-done
+
+			if name, _ := fetch("u1"); name != "Grace" {
+				t.Fatalf("expected the load started before the invalidation not to be cached, got %q", name)
+			}
+		})
+	}
+}
+
+func TestCache_InvalidateAllFreesEntries(t *testing.T) {
+	store := NewMemoryStore(0, LRU)
+	cache := New(Config{Store: store})
+	setup(t, cache, "Search", "Suggest")
+
+	search := aspect.Wrap1R("Search", func(query string) int { return len(query) })
+	suggest := aspect.Wrap1R("Suggest", func(query string) int { return len(query) })
+	search("go")
+	search("rust")
+	suggest("go")
+
+	cache.InvalidateAll("Search")
+	if store.Len() != 1 {
+		t.Fatalf("expected only the entry of 'Suggest' to remain, got %d entries", store.Len())
+	}
+}
+
+func TestCache_ReadsDoNotCreateState(t *testing.T) {
+	cache := New(Config{})
+	if stats := cache.Stats("Unknown"); stats != (Stats{}) {
+		t.Fatalf("expected zero stats, got %+v", stats)
+	}
+	cache.Invalidate("Unknown", Key("u1"))
+	cache.InvalidateAll("Unknown")
+	if len(cache.functions) != 0 {
+		t.Fatalf("expected no state for functions never called, got %d", len(cache.functions))
+	}
+}
+
+func TestMemoryStore_Eviction(t *testing.T) {
+	lru := NewMemoryStore(2, LRU)
+	lru.Set("a", Entry{})
+	lru.Set("b", Entry{})
+	lru.Get("a")
+	lru.Set("c", Entry{})
+	if _, found := lru.Get("b"); found {
+		t.Error("expected LRU to evict the least recently used entry")
+	}
+	if _, found := lru.Get("a"); !found {
+		t.Error("expected LRU to keep the recently used entry")
+	}
+
+	lfu := NewMemoryStore(2, LFU)
+	lfu.Set("a", Entry{})
+	lfu.Get("a")
+	lfu.Get("a")
+	lfu.Set("b", Entry{})
+	lfu.Set("c", Entry{})
+	if _, found := lfu.Get("b"); found {
+		t.Error("expected LFU to evict the least frequently used entry")
+	}
+	if _, found := lfu.Get("a"); !found || lfu.Len() != 2 {
+		t.Errorf("expected LFU to keep the frequently used entry, len %d", lfu.Len())
+	}
+
+	lfu.Delete("a")
+	if _, found := lfu.Get("a"); found || lfu.Len() != 1 {
+		t.Errorf("expected deleted entry to be gone, len %d", lfu.Len())
+	}
+}
+
+func TestMemoryStore_SweepsDeadEntries(t *testing.T) {
+	store := NewMemoryStore(0, LRU)
+	now := time.Unix(1_000, 0)
+
+	store.Set("forever", Entry{StoredAt: now})
+	for i := range minSweepInterval - 2 {
+		store.Set(Key("dead", i), Entry{StoredAt: now, DeleteAt: now.Add(time.Second)})
+	}
+
+	// The next Set reaches the sweep interval and drops every entry dead by its StoredAt
+	store.Set("fresh", Entry{StoredAt: now.Add(time.Minute), DeleteAt: now.Add(2 * time.Minute)})
+	if store.Len() != 2 {
+		t.Fatalf("expected only the live entries to remain, got %d", store.Len())
+	}
+	if _, found := store.Get("forever"); !found {
+		t.Fatal("expected the entry without DeleteAt to be kept")
+	}
+}
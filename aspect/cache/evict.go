@@ -0,0 +1,106 @@
+// Package cache - evict provides advice that evicts or refreshes another function's cache entries
+// after a successful call (e.g. UpdateUserProfile invalidating FetchUserProfile)
+package cache
+
+import (
+	"fmt"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const defaultEvictName = "cache-evict"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// EvictConfig configures an Evictor. Zero values fall back to defaults.
+type EvictConfig struct {
+	Name     string // Name of the advice, for runtime switches (default "cache-evict").
+	Priority int    // Priority of the AfterReturning advice.
+
+	Cache  *Cache // Cache holds the Target entries (required).
+	Target string // Target is the function whose entries are evicted or refreshed (required).
+
+	// Key derives the Target key from the current call, e.g. Args(0) or Result(0).
+	// Nil evicts every Target entry.
+	Key KeyFunc
+	// Put, if set, stores the returned values as the Target entry under Key instead of evicting it
+	// (cache-put). Requires Key.
+	Put func(ctx *aspect.Context) []any
+}
+
+// Evictor is an aspect that keeps another function's cache consistent with the functions it is applied to.
+// It runs as AfterReturning advice, so failed calls leave the cache untouched.
+type Evictor struct {
+	config EvictConfig
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// NewEvictor creates an Evictor, filling unset configuration with defaults.
+func NewEvictor(config EvictConfig) *Evictor {
+	if config.Name == "" {
+		config.Name = defaultEvictName
+	}
+	return &Evictor{config: config}
+}
+
+// Name returns the advice name.
+func (evictor *Evictor) Name() string {
+	return evictor.config.Name
+}
+
+// Advice returns the AfterReturning advice updating the Target cache.
+func (evictor *Evictor) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     evictor.config.Name,
+			Type:     aspect.AfterReturning,
+			Priority: evictor.config.Priority,
+			Handler:  evictor.afterReturning,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (evictor *Evictor) Init() error {
+	switch {
+	case evictor.config.Cache == nil:
+		return fmt.Errorf("evictor '%s': cache is required", evictor.config.Name)
+	case evictor.config.Target == "":
+		return fmt.Errorf("evictor '%s': target function is required", evictor.config.Name)
+	case evictor.config.Put != nil && evictor.config.Key == nil:
+		return fmt.Errorf("evictor '%s': put requires a key", evictor.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; an Evictor holds no state.
+func (evictor *Evictor) Close() error {
+	return nil
+}
+
+// Result derives keys from the current call's return value at index, for use as EvictConfig.Key.
+func Result(index int) KeyFunc {
+	return func(ctx *aspect.Context) string {
+		return Key(ctx.GetResult(index))
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// afterReturning evicts or refreshes the Target entry derived from the successful call.
+func (evictor *Evictor) afterReturning(ctx *aspect.Context) error {
+	cache, target := evictor.config.Cache, evictor.config.Target
+
+	switch {
+	case evictor.config.Key == nil:
+		cache.InvalidateAll(target)
+	case evictor.config.Put != nil:
+		cache.Put(target, evictor.config.Key(ctx), evictor.config.Put(ctx)...)
+	default:
+		cache.Invalidate(target, evictor.config.Key(ctx))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Package cache - evict_test validates cross-function eviction and cache-put advice
+package cache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// profiles is a fake user store shared by the wrapped functions.
+type profiles struct {
+	names map[string]string
+	reads int
+}
+
+// wrapProfiles registers FetchUserProfile with the cache and returns the wrapped read function.
+func wrapProfiles(t *testing.T, cache *Cache, store *profiles, writers ...string) func(string) (string, error) {
+	t.Helper()
+	setup(t, cache, "FetchUserProfile")
+	for _, writer := range writers {
+		aspect.MustRegister(writer)
+	}
+
+	return aspect.Wrap1RE("FetchUserProfile", func(userID string) (string, error) {
+		store.reads++
+		return store.names[userID], nil
+	})
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestEvictor_EvictsByArgument(t *testing.T) {
+	cache := New(Config{})
+	store := &profiles{names: map[string]string{"u1": "Ada", "u2": "Alan"}}
+	fetch := wrapProfiles(t, cache, store, "UpdateUserProfile")
+
+	aspect.MustApply(aspect.On("UpdateUserProfile"), NewEvictor(EvictConfig{
+		Cache:  cache,
+		Target: "FetchUserProfile",
+		Key:    Args(0),
+	}))
+
+	failNext := false
+	update := aspect.Wrap2E("UpdateUserProfile", func(userID, name string) error {
+		if failNext {
+			return errors.New("write failed")
+		}
+		store.names[userID] = name
+		return nil
+	})
+
+	_, _ = fetch("u1")
+	_, _ = fetch("u2")
+	if err := update("u1", "Grace"); err != nil {
+		t.Fatalf("unexpected update error: %v", err)
+	}
+
+	if name, _ := fetch("u1"); name != "Grace" {
+		t.Fatalf("expected evicted entry to be reloaded, got %q", name)
+	}
+	_, _ = fetch("u2")
+	if store.reads != 3 {
+		t.Fatalf("expected only u1 to be reloaded (3 reads), got %d", store.reads)
+	}
+
+	// Failed updates leave the cache untouched
+	failNext = true
+	_ = update("u1", "Barbara")
+	_, _ = fetch("u1")
+	if store.reads != 3 {
+		t.Fatalf("expected failed update not to evict, got %d reads", store.reads)
+	}
+}
+
+func TestEvictor_PutsResult(t *testing.T) {
+	cache := New(Config{})
+	store := &profiles{names: map[string]string{"u1": "Ada"}}
+	fetch := wrapProfiles(t, cache, store, "RenameUser")
+
+	aspect.MustApply(aspect.On("RenameUser"), NewEvictor(EvictConfig{
+		Cache:  cache,
+		Target: "FetchUserProfile",
+		Key:    Args(0),
+		Put:    func(ctx *aspect.Context) []any { return []any{ctx.GetResult(0)} },
+	}))
+
+	rename := aspect.Wrap2RE("RenameUser", func(userID, name string) (string, error) {
+		store.names[userID] = name
+		return name, nil
+	})
+
+	_, _ = fetch("u1")
+	_, _ = rename("u1", "Grace")
+	if name, _ := fetch("u1"); name != "Grace" || store.reads != 1 {
+		t.Fatalf("expected put entry without reload, got %q after %d reads", name, store.reads)
+	}
+}
+
+func TestEvictor_EvictsAllWithoutKey(t *testing.T) {
+	cache := New(Config{})
+	store := &profiles{names: map[string]string{"u1": "Ada", "u2": "Alan"}}
+	fetch := wrapProfiles(t, cache, store, "ImportUsers")
+
+	aspect.MustApply(aspect.On("ImportUsers"), NewEvictor(EvictConfig{Cache: cache, Target: "FetchUserProfile"}))
+	importUsers := aspect.Wrap0("ImportUsers", func() {})
+
+	_, _ = fetch("u1")
+	_, _ = fetch("u2")
+	importUsers()
+	_, _ = fetch("u1")
+	_, _ = fetch("u2")
+	if store.reads != 4 {
+		t.Fatalf("expected every entry to be reloaded (4 reads), got %d", store.reads)
+	}
+}
+
+func TestEvictor_InitValidatesConfig(t *testing.T) {
+	if err := NewEvictor(EvictConfig{Target: "FetchUserProfile"}).Init(); err == nil {
+		t.Error("expected error without cache")
+	}
+	if err := NewEvictor(EvictConfig{Cache: New(Config{})}).Init(); err == nil {
+		t.Error("expected error without target")
+	}
+	put := func(ctx *aspect.Context) []any { return nil }
+	if err := NewEvictor(EvictConfig{Cache: New(Config{}), Target: "FetchUserProfile", Put: put}).Init(); err == nil {
+		t.Error("expected error for put without key")
+	}
+}
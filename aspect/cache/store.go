@@ -0,0 +1,240 @@
+// Package cache - store defines pluggable cache backends and a bounded in-memory store
+package cache
+
+import (
+	"container/heap"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	LRU Eviction = iota // LRU evicts the least recently used entry.
+	LFU                 // LFU evicts the least frequently used entry, the least recently used among ties.
+)
+
+// minSweepInterval is the minimum number of Set calls between two sweeps of dead entries.
+const minSweepInterval = 64
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Eviction selects the entry a bounded MemoryStore drops when full.
+type Eviction int
+
+// Entry is a cached invocation outcome.
+type Entry struct {
+	Results   []any     // Results are the wrapped function's return values.
+	Err       error     // Err is the returned error (only cached when Config.ErrorTTL is set).
+	StoredAt  time.Time // StoredAt is the time the entry was stored.
+	ExpiresAt time.Time // ExpiresAt is the time the entry goes stale (zero if it never does).
+	DeleteAt  time.Time // DeleteAt is the time the entry is no longer served, even stale (zero if never).
+}
+
+// Store is a cache backend. Implementations must be safe for concurrent use and should
+// report backend failures as misses, since the wrapped function is then simply called.
+type Store interface {
+	Get(key string) (Entry, bool)
+	Set(key string, entry Entry)
+	Delete(key string)
+	Clear()
+}
+
+// PrefixDeleter is implemented by stores able to drop every key starting with a prefix.
+// Cache.InvalidateAll uses it to free invalidated entries at once instead of leaving them
+// to expiry or eviction.
+type PrefixDeleter interface {
+	DeletePrefix(prefix string)
+}
+
+// MemoryStore is an in-memory Store, optionally bounded by entry count. Entries past their
+// DeleteAt are swept periodically on Set, so expired entries do not accumulate.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	eviction   Eviction
+	items      map[string]*item
+	order      itemHeap
+	tick       uint64
+	sets       int // sets counts Set calls since the last sweep.
+}
+
+// item is a stored entry with its usage, positioned in the eviction heap.
+type item struct {
+	key   string
+	entry Entry
+	uses  uint64
+	used  uint64
+	index int
+}
+
+// itemHeap orders items by eviction priority, the next victim first.
+type itemHeap struct {
+	items    []*item
+	eviction Eviction
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// NewMemoryStore creates an in-memory store holding at most maxEntries entries (unbounded if <= 0).
+func NewMemoryStore(maxEntries int, eviction Eviction) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		eviction:   eviction,
+		items:      make(map[string]*item),
+		order:      itemHeap{eviction: eviction},
+	}
+}
+
+// Get returns the entry stored under key and records the access.
+func (store *MemoryStore) Get(key string) (Entry, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	stored, exists := store.items[key]
+	if !exists {
+		return Entry{}, false
+	}
+	store.touch(stored)
+	return stored.entry, true
+}
+
+// Set stores entry under key, evicting an entry if the store is full. Every so often it also
+// drops entries whose DeleteAt precedes the new entry's StoredAt.
+func (store *MemoryStore) Set(key string, entry Entry) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.sets++
+	if store.sets >= max(len(store.items), minSweepInterval) {
+		store.sweep(entry.StoredAt)
+	}
+
+	if stored, exists := store.items[key]; exists {
+		stored.entry = entry
+		store.touch(stored)
+		return
+	}
+
+	if store.maxEntries > 0 && len(store.items) >= store.maxEntries {
+		victim := heap.Pop(&store.order).(*item)
+		delete(store.items, victim.key)
+	}
+
+	store.tick++
+	stored := &item{key: key, entry: entry, uses: 1, used: store.tick}
+	store.items[key] = stored
+	heap.Push(&store.order, stored)
+}
+
+// Delete removes the entry stored under key.
+func (store *MemoryStore) Delete(key string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if stored, exists := store.items[key]; exists {
+		heap.Remove(&store.order, stored.index)
+		delete(store.items, key)
+	}
+}
+
+// DeletePrefix removes every entry whose key starts with prefix.
+func (store *MemoryStore) DeletePrefix(prefix string) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.removeIf(func(stored *item) bool { return strings.HasPrefix(stored.key, prefix) })
+}
+
+// Clear removes all entries.
+func (store *MemoryStore) Clear() {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.items = make(map[string]*item)
+	store.order.items = nil
+}
+
+// Len returns the number of stored entries.
+func (store *MemoryStore) Len() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return len(store.items)
+}
+
+// String returns the eviction policy name implementing fmt.Stringer interface.
+func (eviction Eviction) String() string {
+	switch eviction {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// sweep removes entries that can no longer be served at now. Caller holds store.mu.
+func (store *MemoryStore) sweep(now time.Time) {
+	store.sets = 0
+	store.removeIf(func(stored *item) bool {
+		return !stored.entry.DeleteAt.IsZero() && stored.entry.DeleteAt.Before(now)
+	})
+}
+
+// removeIf removes every item matching the predicate and rebuilds the heap. Caller holds store.mu.
+func (store *MemoryStore) removeIf(matches func(stored *item) bool) {
+	kept := store.order.items[:0]
+	for _, stored := range store.order.items {
+		if matches(stored) {
+			delete(store.items, stored.key)
+			continue
+		}
+		stored.index = len(kept)
+		kept = append(kept, stored)
+	}
+	clear(store.order.items[len(kept):])
+	store.order.items = kept
+	heap.Init(&store.order)
+}
+
+// touch records an access and repositions the item. Caller holds store.mu.
+func (store *MemoryStore) touch(stored *item) {
+	store.tick++
+	stored.uses++
+	stored.used = store.tick
+	heap.Fix(&store.order, stored.index)
+}
+
+func (order itemHeap) Len() int { return len(order.items) }
+
+func (order itemHeap) Less(i, j int) bool {
+	a, b := order.items[i], order.items[j]
+	if order.eviction == LFU && a.uses != b.uses {
+		return a.uses < b.uses
+	}
+	return a.used < b.used
+}
+
+func (order itemHeap) Swap(i, j int) {
+	order.items[i], order.items[j] = order.items[j], order.items[i]
+	order.items[i].index = i
+	order.items[j].index = j
+}
+
+func (order *itemHeap) Push(x any) {
+	stored := x.(*item)
+	stored.index = len(order.items)
+	order.items = append(order.items, stored)
+}
+
+func (order *itemHeap) Pop() any {
+	last := len(order.items) - 1
+	stored := order.items[last]
+	order.items[last] = nil
+	order.items = order.items[:last]
+	return stored
+}
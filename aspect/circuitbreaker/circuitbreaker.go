@@ -0,0 +1,388 @@
+// Package circuitbreaker - circuitbreaker provides an aspect that stops calling failing functions
+// and probes them for recovery (CLOSED -> OPEN -> HALF_OPEN -> CLOSED)
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	Closed   State = iota // Closed lets calls through and records their outcome.
+	Open                  // Open rejects calls with ErrCircuitOpen until OpenTimeout elapses.
+	HalfOpen              // HalfOpen lets a limited number of probe calls through to test recovery.
+)
+
+const (
+	defaultName              = "circuit-breaker"
+	defaultOpenTimeout       = 30 * time.Second
+	defaultHalfOpenProbes    = 1
+	defaultConsecutiveFailed = 5
+	defaultIdleTimeout       = 10 * time.Minute
+	minSweepSize             = 64
+)
+
+// ErrCircuitOpen is matched (via errors.Is) by errors returned for calls rejected by an open circuit.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// -------------------------------------------- Types --------------------------------------------
+
+// State is the state of a single breaker.
+type State int
+
+// Clock provides the current time; inject a fake in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// StateChange describes a breaker transition.
+type StateChange struct {
+	Name string // Name is the circuit breaker aspect name.
+	Key  string // Key identifies the breaker (the function name unless Config.KeyFunc is set).
+	From State
+	To   State
+}
+
+// OpenError is the error set on calls rejected by an open (or saturated half-open) circuit.
+type OpenError struct {
+	Name       string        // Name is the circuit breaker aspect name.
+	Key        string        // Key identifies the breaker that rejected the call.
+	RetryAfter time.Duration // RetryAfter is the time left until the circuit half-opens (0 if half-open).
+}
+
+// Config configures a CircuitBreaker. Zero values fall back to defaults.
+type Config struct {
+	Name     string        // Name of the advice, for runtime switches (default "circuit-breaker").
+	Priority int           // Priority of the Around advice.
+	Policy   PolicyFactory // Policy decides when a closed circuit opens (default ConsecutiveFailures(5)).
+
+	OpenTimeout       time.Duration // OpenTimeout is the time spent OPEN before probing (default 30s).
+	HalfOpenMaxProbes int           // HalfOpenMaxProbes limits concurrent probe calls when HALF_OPEN (default 1).
+	HalfOpenSuccesses int           // HalfOpenSuccesses is the number of successful probes needed to close (default HalfOpenMaxProbes).
+
+	// IsFailure classifies call errors as failures (default: any non-nil error). Panics are always failures.
+	IsFailure func(err error) bool
+	// KeyFunc derives the breaker key for a call (default: the function name, one breaker per function).
+	KeyFunc func(ctx *aspect.Context) string
+	// IdleTimeout forgets a CLOSED breaker unused for that long, bounding memory when keys are per
+	// tenant or per argument (default 10m). Open and half-open breakers are kept.
+	IdleTimeout time.Duration
+	// Clock provides the time (default: the system clock).
+	Clock Clock
+	// OnStateChange is called after every breaker transition (optional).
+	OnStateChange func(change StateChange)
+}
+
+// CircuitBreaker is an aspect holding one breaker per function or key.
+type CircuitBreaker struct {
+	config Config
+
+	mu        sync.Mutex
+	breakers  map[string]*breaker
+	sweepSize int // sweepSize is the number of breakers that triggers the next sweep of idle ones.
+}
+
+// breaker is the state machine of a single circuit.
+type breaker struct {
+	mu         sync.Mutex
+	state      State
+	policy     TripPolicy
+	openedAt   time.Time
+	probes     int
+	successes  int
+	generation uint64    // generation changes on every transition so late outcomes are ignored.
+	lastUsed   time.Time // lastUsed is the time of the last admitted call or recorded outcome.
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a CircuitBreaker, filling unset configuration with defaults.
+func New(config Config) *CircuitBreaker {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.Policy == nil {
+		config.Policy = ConsecutiveFailures(defaultConsecutiveFailed)
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = defaultOpenTimeout
+	}
+	if config.HalfOpenMaxProbes <= 0 {
+		config.HalfOpenMaxProbes = defaultHalfOpenProbes
+	}
+	if config.HalfOpenSuccesses <= 0 {
+		config.HalfOpenSuccesses = config.HalfOpenMaxProbes
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool { return err != nil }
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = func(ctx *aspect.Context) string { return ctx.FunctionName }
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultIdleTimeout
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	return &CircuitBreaker{config: config, breakers: make(map[string]*breaker), sweepSize: minSweepSize}
+}
+
+// Name returns the advice name.
+func (cb *CircuitBreaker) Name() string {
+	return cb.config.Name
+}
+
+// Advice returns the Around advice guarding calls.
+func (cb *CircuitBreaker) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     cb.config.Name,
+			Type:     aspect.Around,
+			Priority: cb.config.Priority,
+			Handler:  cb.around,
+		},
+	}
+}
+
+// Init implements aspect.Aspect; breakers are created lazily per key.
+func (cb *CircuitBreaker) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; it forgets all breakers.
+func (cb *CircuitBreaker) Close() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.breakers = make(map[string]*breaker)
+	cb.sweepSize = minSweepSize
+	return nil
+}
+
+// Keys returns the number of breakers currently held.
+func (cb *CircuitBreaker) Keys() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return len(cb.breakers)
+}
+
+// State returns the current state of the breaker for key (Closed if it has not been used yet).
+func (cb *CircuitBreaker) State(key string) State {
+	b, exists := cb.lookup(key)
+	if !exists {
+		return Closed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Report the half-open transition as soon as it is due, not only on the next call
+	if b.state == Open && !cb.config.Clock.Now().Before(b.openedAt.Add(cb.config.OpenTimeout)) {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Reset forces the breaker for key back to CLOSED; keys that have not been used yet are left alone.
+func (cb *CircuitBreaker) Reset(key string) {
+	b, exists := cb.lookup(key)
+	if !exists {
+		return
+	}
+	b.mu.Lock()
+	from := b.state
+	b.transition(Closed, cb.config.Clock.Now())
+	b.mu.Unlock()
+
+	cb.notify(key, from, Closed)
+}
+
+// String returns the state name implementing fmt.Stringer interface.
+func (state State) String() string {
+	switch state {
+	case Closed:
+		return "CLOSED"
+	case Open:
+		return "OPEN"
+	case HalfOpen:
+		return "HALF_OPEN"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Error implements the error interface.
+func (err *OpenError) Error() string {
+	if err.RetryAfter > 0 {
+		return fmt.Sprintf("circuit breaker '%s' is open for '%s', retry in %v", err.Name, err.Key, err.RetryAfter)
+	}
+	return fmt.Sprintf("circuit breaker '%s' is half-open for '%s', probe limit reached", err.Name, err.Key)
+}
+
+// Unwrap makes OpenError match ErrCircuitOpen.
+func (err *OpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+// Now returns the system time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around rejects calls while the circuit is open, otherwise proceeds and records the outcome.
+func (cb *CircuitBreaker) around(ctx *aspect.Context) error {
+	key := cb.config.KeyFunc(ctx)
+	b := cb.breaker(key)
+
+	generation, retryAfter, allowed := cb.allow(key, b)
+	if !allowed {
+		ctx.Error = &OpenError{Name: cb.config.Name, Key: key, RetryAfter: retryAfter}
+		ctx.Skipped = true
+		return nil
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			cb.record(key, b, generation, false)
+			panic(r)
+		}
+	}()
+
+	err := ctx.Proceed()
+	cb.record(key, b, generation, !cb.config.IsFailure(err))
+	return nil
+}
+
+// breaker returns the breaker for key, creating it on first use and sweeping idle breakers
+// whenever their number has doubled since the last sweep.
+func (cb *CircuitBreaker) breaker(key string) *breaker {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b, exists := cb.breakers[key]
+	if !exists {
+		if len(cb.breakers) >= cb.sweepSize {
+			cb.sweep(cb.config.Clock.Now())
+		}
+		b = &breaker{policy: cb.config.Policy()}
+		cb.breakers[key] = b
+	}
+	return b
+}
+
+// sweep forgets CLOSED breakers idle for longer than IdleTimeout. Caller holds cb.mu.
+func (cb *CircuitBreaker) sweep(now time.Time) {
+	for key, b := range cb.breakers {
+		b.mu.Lock()
+		idle := b.state == Closed && now.Sub(b.lastUsed) > cb.config.IdleTimeout
+		b.mu.Unlock()
+		if idle {
+			delete(cb.breakers, key)
+		}
+	}
+	cb.sweepSize = max(2*len(cb.breakers), minSweepSize)
+}
+
+// lookup returns the breaker for key without creating it.
+func (cb *CircuitBreaker) lookup(key string) (*breaker, bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	b, exists := cb.breakers[key]
+	return b, exists
+}
+
+// allow admits a call, moving OPEN to HALF_OPEN once the timeout elapsed.
+func (cb *CircuitBreaker) allow(key string, b *breaker) (generation uint64, retryAfter time.Duration, allowed bool) {
+	now := cb.config.Clock.Now()
+
+	b.mu.Lock()
+	b.lastUsed = now
+	halfOpened := false
+	if b.state == Open {
+		if reopenAt := b.openedAt.Add(cb.config.OpenTimeout); now.Before(reopenAt) {
+			b.mu.Unlock()
+			return 0, reopenAt.Sub(now), false
+		}
+		b.transition(HalfOpen, now)
+		halfOpened = true
+	}
+
+	allowed = b.state != HalfOpen || b.probes < cb.config.HalfOpenMaxProbes
+	if allowed && b.state == HalfOpen {
+		b.probes++
+	}
+	generation = b.generation
+	b.mu.Unlock()
+
+	if halfOpened {
+		cb.notify(key, Open, HalfOpen)
+	}
+	return generation, 0, allowed
+}
+
+// record feeds a call outcome into the breaker, ignoring calls admitted before the last transition.
+func (cb *CircuitBreaker) record(key string, b *breaker, generation uint64, success bool) {
+	now := cb.config.Clock.Now()
+
+	b.mu.Lock()
+	b.lastUsed = now
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+
+	from := b.state
+	switch b.state {
+	case Closed:
+		if success {
+			b.policy.OnSuccess(now)
+		} else if b.policy.OnFailure(now) {
+			b.transition(Open, now)
+		}
+	case HalfOpen:
+		b.probes--
+		if !success {
+			b.transition(Open, now)
+		} else if b.successes++; b.successes >= cb.config.HalfOpenSuccesses {
+			b.transition(Closed, now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	if from != to {
+		cb.notify(key, from, to)
+	}
+}
+
+// notify reports a transition to the OnStateChange callback.
+func (cb *CircuitBreaker) notify(key string, from, to State) {
+	if cb.config.OnStateChange != nil && from != to {
+		cb.config.OnStateChange(StateChange{Name: cb.config.Name, Key: key, From: from, To: to})
+	}
+}
+
+// transition moves the breaker to a new state and starts a new generation. Caller holds b.mu.
+func (b *breaker) transition(to State, now time.Time) {
+	b.state = to
+	b.generation++
+	b.probes = 0
+	b.successes = 0
+	switch to {
+	case Open:
+		b.openedAt = now
+	case Closed:
+		b.policy.Reset()
+	}
+}
@@ -0,0 +1,259 @@
+// Package circuitbreaker - circuitbreaker_test validates breaker state transitions and trip policies
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+var errUnavailable = errors.New("service unavailable")
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time { return clock.now }
+
+func (clock *fakeClock) Advance(d time.Duration) { clock.now = clock.now.Add(d) }
+
+// setup registers functions on a fresh global registry and applies the circuit breaker to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestCircuitBreaker_OpensAndRecovers(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	var changes []StateChange
+	cb := New(Config{
+		Policy:        ConsecutiveFailures(3),
+		OpenTimeout:   5 * time.Second,
+		Clock:         clock,
+		OnStateChange: func(change StateChange) { changes = append(changes, change) },
+	})
+	setup(t, cb, "CallExternalService")
+
+	healthy := false
+	calls := 0
+	call := aspect.Wrap1RE("CallExternalService", func(endpoint string) (string, error) {
+		calls++
+		if !healthy {
+			return "", errUnavailable
+		}
+		return "ok", nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, err := call("/api"); !errors.Is(err, errUnavailable) {
+			t.Fatalf("call %d: expected service error, got %v", i, err)
+		}
+	}
+	if cb.State("CallExternalService") != Open {
+		t.Fatalf("expected OPEN after 3 failures, got %v", cb.State("CallExternalService"))
+	}
+
+	// Rejected without calling the target, with a typed error
+	_, err := call("/api")
+	var openErr *OpenError
+	if !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &openErr) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if openErr.RetryAfter != 5*time.Second || calls != 3 {
+		t.Fatalf("expected 5s retry-after and no target call, got %v and %d calls", openErr.RetryAfter, calls)
+	}
+
+	// After the timeout a probe is let through and closes the circuit on success
+	clock.Advance(5 * time.Second)
+	healthy = true
+	if result, err := call("/api"); err != nil || result != "ok" {
+		t.Fatalf("expected probe to succeed, got (%q, %v)", result, err)
+	}
+	if cb.State("CallExternalService") != Closed {
+		t.Fatalf("expected CLOSED after successful probe, got %v", cb.State("CallExternalService"))
+	}
+
+	expected := []StateChange{
+		{Name: "circuit-breaker", Key: "CallExternalService", From: Closed, To: Open},
+		{Name: "circuit-breaker", Key: "CallExternalService", From: Open, To: HalfOpen},
+		{Name: "circuit-breaker", Key: "CallExternalService", From: HalfOpen, To: Closed},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, expected[i], changes[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cb := New(Config{Policy: ConsecutiveFailures(1), OpenTimeout: time.Second, Clock: clock})
+	setup(t, cb, "Flaky")
+
+	flaky := aspect.Wrap0RE("Flaky", func() (int, error) { return 0, errUnavailable })
+
+	_, _ = flaky()
+	clock.Advance(time.Second)
+	_, _ = flaky() // probe fails
+
+	if cb.State("Flaky") != Open {
+		t.Fatalf("expected OPEN after failed probe, got %v", cb.State("Flaky"))
+	}
+	if _, err := flaky(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected rejection after reopening, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenProbeLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cb := New(Config{Policy: ConsecutiveFailures(1), OpenTimeout: time.Second, HalfOpenMaxProbes: 1, Clock: clock})
+	setup(t, cb, "Slow")
+
+	var nested func() (int, error)
+	failing := true
+	var probeRejection error
+	slow := aspect.Wrap0RE("Slow", func() (int, error) {
+		if failing {
+			return 0, errUnavailable
+		}
+		// While the single probe is in flight, a concurrent call is rejected
+		_, probeRejection = nested()
+		return 1, nil
+	})
+	nested = slow
+
+	_, _ = slow()
+	clock.Advance(time.Second)
+	failing = false
+	if _, err := slow(); err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+
+	var openErr *OpenError
+	if !errors.As(probeRejection, &openErr) || openErr.RetryAfter != 0 {
+		t.Fatalf("expected half-open rejection, got %v", probeRejection)
+	}
+}
+
+func TestCircuitBreaker_PerKeyBreakers(t *testing.T) {
+	cb := New(Config{
+		Policy:  ConsecutiveFailures(1),
+		KeyFunc: func(ctx *aspect.Context) string { return ctx.Args[0].(string) },
+	})
+	setup(t, cb, "CallTenant")
+
+	call := aspect.Wrap1E("CallTenant", func(tenant string) error {
+		if tenant == "broken" {
+			return errUnavailable
+		}
+		return nil
+	})
+
+	_ = call("broken")
+	if err := call("broken"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected broken tenant circuit to be open, got %v", err)
+	}
+	if err := call("healthy"); err != nil {
+		t.Fatalf("expected healthy tenant unaffected, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_InspectionDoesNotCreateBreakers(t *testing.T) {
+	var changes int
+	cb := New(Config{OnStateChange: func(change StateChange) { changes++ }})
+
+	if state := cb.State("Unknown"); state != Closed {
+		t.Fatalf("expected CLOSED for an unused key, got %v", state)
+	}
+	cb.Reset("Unknown")
+	if len(cb.breakers) != 0 || changes != 0 {
+		t.Fatalf("expected no breaker and no transition, got %d breakers and %d changes", len(cb.breakers), changes)
+	}
+}
+
+func TestCircuitBreaker_ForgetsIdleClosedBreakers(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	cb := New(Config{
+		Policy:      ConsecutiveFailures(1),
+		IdleTimeout: time.Minute,
+		KeyFunc:     func(ctx *aspect.Context) string { return fmt.Sprint(ctx.Args[0]) },
+		Clock:       clock,
+	})
+	setup(t, cb, "Lookup")
+	lookup := aspect.Wrap1E("Lookup", func(id int) error {
+		if id == 1 {
+			return errors.New("down")
+		}
+		return nil
+	})
+
+	for id := range minSweepSize {
+		_ = lookup(id)
+	}
+	clock.Advance(2 * time.Minute)
+
+	// The next new key finds minSweepSize breakers and sweeps the idle closed ones
+	_ = lookup(minSweepSize)
+	if keys := cb.Keys(); keys != 2 {
+		t.Fatalf("expected the open breaker and the new one to remain, got %d keys", keys)
+	}
+	if cb.State("1") == Closed {
+		t.Fatal("expected the open breaker to be kept")
+	}
+}
+
+func TestFailureRate_SlidingWindow(t *testing.T) {
+	policy := FailureRate(0.5, 10*time.Second, 4)()
+	now := time.Unix(1_000, 0)
+
+	policy.OnSuccess(now)
+	policy.OnSuccess(now)
+	if policy.OnFailure(now) {
+		t.Fatal("expected no trip below minimum calls")
+	}
+	if !policy.OnFailure(now) {
+		t.Fatal("expected trip at 50% failures over 4 calls")
+	}
+
+	// Outcomes older than the window no longer count
+	policy.Reset()
+	policy.OnFailure(now)
+	policy.OnFailure(now)
+	later := now.Add(20 * time.Second)
+	policy.OnSuccess(later)
+	policy.OnSuccess(later)
+	policy.OnSuccess(later)
+	if policy.OnFailure(later) {
+		t.Fatal("expected old failures to fall out of the window")
+	}
+}
+
+func TestFailureRate_ClockBefore1970(t *testing.T) {
+	policy := FailureRate(0.5, 10*time.Second, 2)()
+	var now time.Time
+
+	policy.OnSuccess(now)
+	if !policy.OnFailure(now.Add(time.Second)) {
+		t.Fatal("expected trip at 50% failures with a zero clock")
+	}
+}
@@ -0,0 +1,122 @@
+// Package circuitbreaker - policy provides trip policies deciding when a closed circuit opens
+package circuitbreaker
+
+import "time"
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// windowBuckets is the number of buckets a sliding window is divided into.
+const windowBuckets = 10
+
+// -------------------------------------------- Types --------------------------------------------
+
+// TripPolicy observes outcomes of calls while the circuit is CLOSED and decides when it opens.
+// A TripPolicy is used by a single breaker and is never called concurrently.
+type TripPolicy interface {
+	OnSuccess(now time.Time)             // OnSuccess records a successful call.
+	OnFailure(now time.Time) (trip bool) // OnFailure records a failed call and reports whether to open.
+	Reset()                              // Reset forgets all recorded outcomes (called when the circuit closes).
+}
+
+// PolicyFactory creates a fresh TripPolicy for each breaker (one per function or key).
+type PolicyFactory func() TripPolicy
+
+// consecutiveFailures trips after a number of failures in a row.
+type consecutiveFailures struct {
+	threshold int
+	failures  int
+}
+
+// failureRate trips when the failure ratio within a sliding time window reaches a threshold.
+type failureRate struct {
+	threshold    float64
+	minimumCalls int
+	bucketWidth  time.Duration
+	buckets      [windowBuckets]bucket
+}
+
+// bucket counts outcomes within one slice of the sliding window.
+type bucket struct {
+	start     time.Time
+	successes int
+	failures  int
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// ConsecutiveFailures opens the circuit after threshold failures in a row.
+func ConsecutiveFailures(threshold int) PolicyFactory {
+	return func() TripPolicy {
+		return &consecutiveFailures{threshold: max(threshold, 1)}
+	}
+}
+
+// FailureRate opens the circuit when at least minimumCalls were made within the sliding window
+// and the ratio of failures among them reaches threshold (0 < threshold <= 1).
+func FailureRate(threshold float64, window time.Duration, minimumCalls int) PolicyFactory {
+	return func() TripPolicy {
+		return &failureRate{
+			threshold:    threshold,
+			minimumCalls: max(minimumCalls, 1),
+			bucketWidth:  max(window/windowBuckets, time.Nanosecond),
+		}
+	}
+}
+
+// OnSuccess ends the current run of failures.
+func (policy *consecutiveFailures) OnSuccess(now time.Time) {
+	policy.failures = 0
+}
+
+// OnFailure extends the run of failures and trips once it reaches the threshold.
+func (policy *consecutiveFailures) OnFailure(now time.Time) bool {
+	policy.failures++
+	return policy.failures >= policy.threshold
+}
+
+// Reset forgets the current run of failures.
+func (policy *consecutiveFailures) Reset() {
+	policy.failures = 0
+}
+
+// OnSuccess counts a success in the bucket covering now.
+func (policy *failureRate) OnSuccess(now time.Time) {
+	policy.current(now).successes++
+}
+
+// OnFailure counts a failure in the bucket covering now and trips once the window holds enough
+// calls and its failure ratio reaches the threshold.
+func (policy *failureRate) OnFailure(now time.Time) bool {
+	policy.current(now).failures++
+
+	var successes, failures int
+	oldest := now.Add(-policy.bucketWidth * windowBuckets)
+	for _, b := range policy.buckets {
+		if b.start.After(oldest) {
+			successes += b.successes
+			failures += b.failures
+		}
+	}
+
+	calls := successes + failures
+	return calls >= policy.minimumCalls && float64(failures)/float64(calls) >= policy.threshold
+}
+
+// Reset empties every bucket of the window.
+func (policy *failureRate) Reset() {
+	policy.buckets = [windowBuckets]bucket{}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// current returns the bucket covering now, recycling it if it belongs to an older window.
+func (policy *failureRate) current(now time.Time) *bucket {
+	slot := now.UnixNano() / int64(policy.bucketWidth)
+	b := &policy.buckets[((slot%windowBuckets)+windowBuckets)%windowBuckets] // Slots are negative before 1970
+
+	start := time.Unix(0, slot*int64(policy.bucketWidth))
+	if !b.start.Equal(start) {
+		*b = bucket{start: start}
+	}
+	return b
+}
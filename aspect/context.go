@@ -1,7 +1,18 @@
 // Package aspect - context provides execution context for aspect-oriented advice
 package aspect
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/pprof"
+	"slices"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ErrProceedUnavailable is returned by Proceed when called outside Around advice.
+var ErrProceedUnavailable = errors.New("proceed is only available inside Around advice")
 
 // -------------------------------------------- Types --------------------------------------------
 
@@ -14,7 +25,21 @@ type Context struct {
 	Error        error          // Error holds any error returned by the function.
 	PanicValue   any            // PanicValue holds the recovered panic value if a panic occurred.
 	Metadata     map[string]any // Metadata allows storing custom key-value pairs for advice communication.
-	Skipped      bool           // Skipped indicates if the target function execution should be skipped (set by Around advice).
+	Skipped      bool           // Skipped indicates if the target function execution should be skipped (set by Around advice, which also skips lower-priority Around advice).
+	Degraded     bool           // Degraded indicates the results are a fallback rather than the target's own (set by Around advice).
+
+	proceed   func(*Context) error // proceed runs the remaining Around advice and the target (set while Around advice runs).
+	redaction redaction            // redaction holds the function's redaction rules, see Redacted.
+	executed  []ExecutedAdvice     // executed lists the advice run so far, once TrackAdvice was called.
+	registry  *Registry            // registry ran the call, see Hold (nil for contexts built with NewContext).
+	labels    context.Context      // labels carries the pprof labels set by advice with DoLabeled (nil if none).
+}
+
+// ExecutedAdvice identifies advice that ran for a call, see Context.TrackAdvice.
+type ExecutedAdvice struct {
+	Type     AdviceType
+	Name     string
+	Priority int
 }
 
 // NewContext creates a new execution context for the given function.
@@ -50,6 +75,73 @@ func (aopCtx *Context) GetResult(index int) any {
 	return aopCtx.Results[index]
 }
 
+// Proceed runs the remaining Around advice and the target function, then returns ctx.Error.
+// Around advice that calls Proceed takes over the rest of the chain: the target is not run again
+// after the advice returns. Calling Proceed several times re-invokes the target (e.g. for retries).
+// Returns ErrProceedUnavailable outside Around advice.
+func (aopCtx *Context) Proceed() error {
+	if aopCtx.proceed == nil {
+		return ErrProceedUnavailable
+	}
+	return aopCtx.proceed(aopCtx)
+}
+
+// Fork returns a copy of the context with its own Args, Results and Metadata. A fork taken inside
+// Around advice can Proceed on its own, e.g. from a goroutine, without affecting the original call's
+// results; once it has proceeded, the original call no longer falls through to the rest of the chain.
+func (aopCtx *Context) Fork() *Context {
+	fork := *aopCtx
+	fork.Args = append([]any(nil), aopCtx.Args...)
+	fork.Results = append([]any(nil), aopCtx.Results...)
+	fork.executed = slices.Clone(aopCtx.executed)
+	fork.Metadata = make(map[string]any, len(aopCtx.Metadata))
+	for key, value := range aopCtx.Metadata {
+		fork.Metadata[key] = value
+	}
+	return &fork
+}
+
+// TrackAdvice starts recording the advice run for the call from now on, for ExecutedAdvice.
+// Call it from high-priority Before advice to see the whole chain.
+func (aopCtx *Context) TrackAdvice() {
+	if aopCtx.executed == nil {
+		aopCtx.executed = make([]ExecutedAdvice, 0, 8)
+	}
+}
+
+// ExecutedAdvice returns the advice run for the call since TrackAdvice, in execution order.
+func (aopCtx *Context) ExecutedAdvice() []ExecutedAdvice {
+	return slices.Clone(aopCtx.executed)
+}
+
+// Context returns the context.Context passed as the first argument of the wrapped function,
+// or context.Background() if the function does not accept one.
+func (aopCtx *Context) Context() context.Context {
+	if len(aopCtx.Args) > 0 {
+		if ctx, ok := aopCtx.Args[0].(context.Context); ok && ctx != nil {
+			return ctx
+		}
+	}
+	return context.Background()
+}
+
+// DoLabeled runs fn with labels added to the pprof labels of the goroutine, as pprof.Do does, and
+// restores them once fn returns. Labels set by higher-priority advice through DoLabeled are kept
+// even for functions that do not accept a context.Context; otherwise those of Context are kept.
+func (aopCtx *Context) DoLabeled(labels pprof.LabelSet, fn func(labeled context.Context)) {
+	previous := aopCtx.labels
+	parent := previous
+	if parent == nil {
+		parent = aopCtx.Context()
+	}
+
+	pprof.Do(parent, labels, func(labeled context.Context) {
+		aopCtx.labels = labeled
+		defer func() { aopCtx.labels = previous }()
+		fn(labeled)
+	})
+}
+
 // HasPanic returns true if a panic was recovered during execution.
 func (aopCtx *Context) HasPanic() bool {
 	return aopCtx.PanicValue != nil
@@ -60,3 +152,12 @@ func (aopCtx *Context) String() string {
 	return fmt.Sprintf("Context{Function: %s, Args: %v, Results: %v, Error: %v, Panic: %v}",
 		aopCtx.FunctionName, aopCtx.Args, aopCtx.Results, aopCtx.Error, aopCtx.PanicValue)
 }
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// track records advice about to run if the context tracks advice.
+func (aopCtx *Context) track(advice Advice) {
+	if aopCtx.executed != nil {
+		aopCtx.executed = append(aopCtx.executed, ExecutedAdvice{Type: advice.Type, Name: advice.Name, Priority: advice.Priority})
+	}
+}
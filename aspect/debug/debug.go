@@ -0,0 +1,148 @@
+// Package debug - debug exposes the registered functions, their advice, runtime switches, call
+// statistics and gauges as JSON over HTTP and through expvar
+package debug
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Snapshot is the introspection view of a registry.
+type Snapshot struct {
+	Enabled   bool       `json:"enabled"` // Enabled is false while the global kill switch is engaged.
+	Frozen    bool       `json:"frozen"`
+	InFlight  int64      `json:"in_flight"`
+	Functions []Function `json:"functions"`
+	Gauges    []Gauge    `json:"gauges,omitempty"`
+}
+
+// Function describes a registered function.
+type Function struct {
+	Name           string            `json:"name"`
+	Tags           map[string]string `json:"tags,omitempty"`
+	Enabled        bool              `json:"enabled"`
+	Stats          Stats             `json:"stats"`
+	Before         []Advice          `json:"before,omitempty"`
+	Around         []Advice          `json:"around,omitempty"`
+	AfterReturning []Advice          `json:"after_returning,omitempty"`
+	AfterThrowing  []Advice          `json:"after_throwing,omitempty"`
+	After          []Advice          `json:"after,omitempty"`
+}
+
+// Advice describes attached advice, listed in execution order.
+type Advice struct {
+	Name     string `json:"name,omitempty"`
+	Priority int    `json:"priority"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// Stats are the call counters of a function.
+type Stats struct {
+	Calls    uint64 `json:"calls"`
+	Errors   uint64 `json:"errors"`
+	Panics   uint64 `json:"panics"`
+	InFlight int64  `json:"in_flight"`
+}
+
+// Gauge is a sampled registry gauge.
+type Gauge struct {
+	Name  string `json:"name"`
+	Key   string `json:"key,omitempty"`
+	Value int64  `json:"value"`
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Take returns the snapshot of a registry, or of the current global registry if nil.
+func Take(registry *aspect.Registry) Snapshot {
+	if registry == nil {
+		registry = aspect.GetGlobalRegistry()
+	}
+
+	names := registry.ListRegistered()
+	sort.Strings(names)
+
+	snapshot := Snapshot{
+		Enabled:   registry.IsEnabled(),
+		Frozen:    registry.IsFrozen(),
+		InFlight:  registry.InFlightTotal(),
+		Functions: make([]Function, 0, len(names)),
+	}
+	for _, name := range names {
+		description, err := registry.Describe(name)
+		if err != nil {
+			continue // Unregistered meanwhile
+		}
+		snapshot.Functions = append(snapshot.Functions, Function{
+			Name:           description.Name,
+			Tags:           description.Tags,
+			Enabled:        description.Enabled,
+			Stats:          Stats(description.Stats),
+			Before:         advice(description.Before),
+			Around:         advice(description.Around),
+			AfterReturning: advice(description.AfterReturning),
+			AfterThrowing:  advice(description.AfterThrowing),
+			After:          advice(description.After),
+		})
+	}
+	for _, gauge := range registry.Gauges() {
+		snapshot.Gauges = append(snapshot.Gauges, Gauge{Name: gauge.Name, Key: gauge.Key, Value: gauge.Value()})
+	}
+	return snapshot
+}
+
+// Handler returns an http.Handler serving the snapshot of a registry (the current global registry
+// if nil) as JSON. The "function" query parameter restricts the functions listed, e.g. ?function=GetUser.
+func Handler(registry *aspect.Registry) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		snapshot := Take(registry)
+		if selected := request.URL.Query()["function"]; len(selected) > 0 {
+			snapshot.Functions = filter(snapshot.Functions, selected)
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(snapshot)
+	})
+}
+
+// Publish publishes the snapshot of a registry (the current global registry if nil) as an expvar
+// variable, served by the expvar handler on /debug/vars. Like expvar.Publish, it panics if the
+// name is already in use.
+func Publish(name string, registry *aspect.Registry) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return Take(registry)
+	}))
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// advice converts advice descriptions.
+func advice(infos []aspect.AdviceInfo) []Advice {
+	adviceList := make([]Advice, len(infos))
+	for index, info := range infos {
+		adviceList[index] = Advice{Name: info.Name, Priority: info.Priority, Enabled: info.Enabled}
+	}
+	return adviceList
+}
+
+// filter keeps the functions with the selected names.
+func filter(functions []Function, selected []string) []Function {
+	kept := make([]Function, 0, len(selected))
+	for _, function := range functions {
+		for _, name := range selected {
+			if function.Name == name {
+				kept = append(kept, function)
+				break
+			}
+		}
+	}
+	return kept
+}
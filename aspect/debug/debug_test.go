@@ -0,0 +1,86 @@
+// Package debug - debug_test validates the introspection handler and expvar publication
+package debug
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup installs a fresh global registry with two functions and some advice.
+func setup(t *testing.T) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	noop := func(ctx *aspect.Context) error { return nil }
+	registry.MustRegister("GetUser", aspect.WithTags(map[string]string{"layer": "repository"}))
+	registry.MustRegister("SaveUser")
+	registry.MustAddAdvice("GetUser", aspect.Advice{Name: "auth", Type: aspect.Before, Priority: 100, Handler: noop})
+	registry.MustAddAdvice("GetUser", aspect.Advice{Name: "cache", Type: aspect.Around, Priority: 50, Handler: noop})
+	registry.MustAddAdvice("GetUser", aspect.Advice{Name: "log", Type: aspect.Before, Priority: 10, Handler: noop})
+	registry.MustAddAdvice("SaveUser", aspect.Advice{Name: "log", Type: aspect.After, Handler: noop})
+	_ = registry.RegisterGauge(aspect.Gauge{Name: "bulkhead.active", Key: "SaveUser", Value: func() int64 { return 2 }})
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestHandler_ListsFunctionsAdviceAndStats(t *testing.T) {
+	registry := setup(t)
+	_ = registry.DisableAdvice("log")
+	_ = registry.DisableFunction("SaveUser")
+
+	getUser := aspect.Wrap1R("GetUser", func(id int) string { return "user" })
+	getUser(1)
+	getUser(2)
+
+	recorder := httptest.NewRecorder()
+	Handler(nil).ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/aspects", nil))
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(recorder.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("expected JSON, got %v:\n%s", err, recorder.Body.String())
+	}
+	if !snapshot.Enabled || len(snapshot.Functions) != 2 || len(snapshot.Gauges) != 1 || snapshot.Gauges[0].Value != 2 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	getUserInfo, saveUserInfo := snapshot.Functions[0], snapshot.Functions[1]
+	if getUserInfo.Name != "GetUser" || getUserInfo.Tags["layer"] != "repository" || getUserInfo.Stats.Calls != 2 {
+		t.Errorf("unexpected GetUser %+v", getUserInfo)
+	}
+	if before := getUserInfo.Before; len(before) != 2 || before[0].Name != "auth" || before[1].Name != "log" || before[1].Enabled {
+		t.Errorf("expected ordered Before advice with 'log' disabled, got %+v", before)
+	}
+	if len(getUserInfo.Around) != 1 || getUserInfo.Around[0].Priority != 50 {
+		t.Errorf("unexpected Around advice %+v", getUserInfo.Around)
+	}
+	if saveUserInfo.Enabled || len(saveUserInfo.After) != 1 {
+		t.Errorf("expected SaveUser to be disabled with its After advice, got %+v", saveUserInfo)
+	}
+
+	filtered := httptest.NewRecorder()
+	Handler(registry).ServeHTTP(filtered, httptest.NewRequest("GET", "/debug/aspects?function=SaveUser", nil))
+	var only Snapshot
+	if err := json.Unmarshal(filtered.Body.Bytes(), &only); err != nil || len(only.Functions) != 1 || only.Functions[0].Name != "SaveUser" {
+		t.Fatalf("expected the filtered function only, got %+v (%v)", only.Functions, err)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	setup(t)
+	Publish("aspects_test", nil)
+
+	var snapshot Snapshot
+	if err := json.Unmarshal([]byte(expvar.Get("aspects_test").String()), &snapshot); err != nil || len(snapshot.Functions) != 2 {
+		t.Fatalf("expected the snapshot as expvar, got %+v (%v)", snapshot, err)
+	}
+}
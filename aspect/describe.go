@@ -0,0 +1,113 @@
+// Package aspect - describe reports the advice attached to registered functions and their call statistics
+package aspect
+
+import "fmt"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// AdviceInfo describes advice attached to a function.
+type AdviceInfo struct {
+	Name     string     // Name is the advice name (may be empty).
+	Type     AdviceType // Type is the advice type.
+	Priority int        // Priority orders advice of the same type.
+	Enabled  bool       // Enabled is false if the advice name was switched off with DisableAdvice.
+}
+
+// CallStats are the call counters of a registered function. Only calls run with advice are counted:
+// calls made while the function or the whole registry is disabled are not.
+type CallStats struct {
+	Calls    uint64 // Calls are the invocations.
+	Errors   uint64 // Errors are invocations that returned with a Context error.
+	Panics   uint64 // Panics are invocations that panicked, including failed Before advice.
+	InFlight int64  // InFlight are the invocations currently running.
+}
+
+// FunctionDescription describes a registered function.
+type FunctionDescription struct {
+	Name    string            // Name is the registered function name.
+	Tags    map[string]string // Tags are the registration tags.
+	Enabled bool              // Enabled is false if the function was switched off with DisableFunction.
+	Stats   CallStats         // Stats are the call counters.
+
+	// Advice per type, in execution order.
+	Before, Around, AfterReturning, AfterThrowing, After []AdviceInfo
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Describe returns the description of a registered function.
+// Returns error if the function is not registered.
+func (registry *Registry) Describe(functionName string) (FunctionDescription, error) {
+	registry.mu.RLock()
+	chain, exists := registry.entries[functionName]
+	registry.mu.RUnlock()
+
+	if !exists {
+		return FunctionDescription{}, fmt.Errorf("function '%s' is not registered", functionName)
+	}
+
+	compiled := chain.compile()
+	disabledAdvice := chain.switches.disabledAdvice()
+	describe := func(adviceList []Advice) []AdviceInfo {
+		infos := make([]AdviceInfo, len(adviceList))
+		for index, advice := range adviceList {
+			_, off := disabledAdvice[advice.Name]
+			infos[index] = AdviceInfo{
+				Name:     advice.Name,
+				Type:     advice.Type,
+				Priority: advice.Priority,
+				Enabled:  !off || advice.Name == "",
+			}
+		}
+		return infos
+	}
+
+	return FunctionDescription{
+		Name:           functionName,
+		Tags:           registry.Tags(functionName),
+		Enabled:        !chain.disabled.Load(),
+		Stats:          chain.stats(),
+		Before:         describe(compiled.before),
+		Around:         describe(compiled.around),
+		AfterReturning: describe(compiled.afterReturning),
+		AfterThrowing:  describe(compiled.afterThrowing),
+		After:          describe(compiled.after),
+	}, nil
+}
+
+// Stats returns the call counters of a registered function.
+// Returns error if the function is not registered.
+func (registry *Registry) Stats(functionName string) (CallStats, error) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	chain, exists := registry.entries[functionName]
+	if !exists {
+		return CallStats{}, fmt.Errorf("function '%s' is not registered", functionName)
+	}
+	return chain.stats(), nil
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// stats reads the call counters of the chain.
+func (ac *AdviceChain) stats() CallStats {
+	return CallStats{
+		Calls:    ac.calls.Load(),
+		Errors:   ac.errors.Load(),
+		Panics:   ac.panics.Load(),
+		InFlight: ac.inflight.Load(),
+	}
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// Describe describes a function of the global registry.
+func Describe(functionName string) (FunctionDescription, error) {
+	return globalRegistry.Describe(functionName)
+}
+
+// Stats returns the call counters of a function of the global registry.
+func Stats(functionName string) (CallStats, error) {
+	return globalRegistry.Stats(functionName)
+}
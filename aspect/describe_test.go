@@ -0,0 +1,61 @@
+// Package aspect - describe_test validates function descriptions and call statistics
+package aspect
+
+import (
+	"errors"
+	"testing"
+)
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestRegistry_Describe(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Describe", WithTags(map[string]string{"layer": "service"}))
+	noop := func(ctx *Context) error { return nil }
+	registry.MustAddAdvice("Describe", Advice{Name: "low", Type: Before, Priority: 1, Handler: noop})
+	registry.MustAddAdvice("Describe", Advice{Name: "high", Type: Before, Priority: 9, Handler: noop})
+	registry.MustAddAdvice("Describe", Advice{Name: "log", Type: After, Handler: noop})
+	_ = registry.DisableAdvice("low")
+
+	description, err := registry.Describe("Describe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !description.Enabled || description.Tags["layer"] != "service" {
+		t.Errorf("unexpected state %+v", description)
+	}
+	if len(description.Before) != 2 || description.Before[0].Name != "high" || description.Before[1].Enabled {
+		t.Errorf("expected Before advice in execution order with 'low' disabled, got %+v", description.Before)
+	}
+	if len(description.After) != 1 || description.After[0].Type != After || len(description.Around) != 0 {
+		t.Errorf("unexpected advice per type %+v", description)
+	}
+
+	if _, err := registry.Describe("Missing"); err == nil {
+		t.Error("expected error for unregistered function")
+	}
+}
+
+func TestRegistry_Stats(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Counted")
+	registry.MustAddAdvice("Counted", Advice{Type: Before, Handler: func(ctx *Context) error { return nil }})
+
+	counted := Wrap1E("Counted", func(fail bool) error {
+		if fail {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	_ = counted(false)
+	_ = counted(true)
+	func() {
+		defer func() { _ = recover() }()
+		Wrap0("Counted", func() { panic("boom") })()
+	}()
+
+	stats, err := registry.Stats("Counted")
+	if err != nil || stats.Calls != 3 || stats.Errors != 1 || stats.Panics != 1 || stats.InFlight != 0 {
+		t.Fatalf("expected 3 calls, 1 error and 1 panic, got %+v (%v)", stats, err)
+	}
+}
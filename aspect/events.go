@@ -0,0 +1,131 @@
+// Package aspect - events publishes registry mutations to subscribers
+package aspect
+
+import "sync"
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	FunctionRegistered   EventType = iota // FunctionRegistered is published when a function is registered.
+	FunctionUnregistered                  // FunctionUnregistered is published when a function is removed.
+	AdviceAdded                           // AdviceAdded is published when advice is attached to a function.
+	AdviceRemoved                         // AdviceRemoved is published when advice is detached from a function.
+	RegistryCleared                       // RegistryCleared is published when all functions are removed.
+	RegistryFrozen                        // RegistryFrozen is published when the registry is frozen.
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// EventType identifies the kind of registry mutation.
+type EventType int
+
+// Event describes a single registry mutation.
+type Event struct {
+	Type         EventType  // Type is the kind of mutation.
+	FunctionName string     // FunctionName is the affected function (empty for registry-wide events).
+	AdviceName   string     // AdviceName is the affected advice name (advice events only, may be empty).
+	AdviceType   AdviceType // AdviceType is the affected advice type (advice events only).
+}
+
+// broadcaster delivers values synchronously to a dynamic set of subscribers.
+// The zero value is ready to use.
+type broadcaster[T any] struct {
+	mu          sync.Mutex
+	subscribers map[int]func(T)
+	nextID      int
+}
+
+// eventBatch collects events under the registry lock and publishes them after it is released,
+// so subscribers may safely call back into the registry.
+type eventBatch struct {
+	bus    *broadcaster[Event]
+	events []Event
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// String returns the event type name implementing fmt.Stringer interface.
+func (eventType EventType) String() string {
+	switch eventType {
+	case FunctionRegistered:
+		return "FunctionRegistered"
+	case FunctionUnregistered:
+		return "FunctionUnregistered"
+	case AdviceAdded:
+		return "AdviceAdded"
+	case AdviceRemoved:
+		return "AdviceRemoved"
+	case RegistryCleared:
+		return "RegistryCleared"
+	case RegistryFrozen:
+		return "RegistryFrozen"
+	default:
+		return "Unknown"
+	}
+}
+
+// Subscribe registers a callback invoked after every registry mutation.
+// Callbacks run synchronously on the mutating goroutine, after the registry lock is released.
+// Returns a function that removes the callback.
+func (registry *Registry) Subscribe(callback func(Event)) (unsubscribe func()) {
+	return registry.events.subscribe(callback)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// subscribe adds a callback and returns its removal function.
+func (bus *broadcaster[T]) subscribe(callback func(T)) func() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+
+	if bus.subscribers == nil {
+		bus.subscribers = make(map[int]func(T))
+	}
+	id := bus.nextID
+	bus.nextID++
+	bus.subscribers[id] = callback
+
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		delete(bus.subscribers, id)
+	}
+}
+
+// publish delivers a value to a snapshot of the current subscribers.
+func (bus *broadcaster[T]) publish(value T) {
+	bus.mu.Lock()
+	callbacks := make([]func(T), 0, len(bus.subscribers))
+	for _, callback := range bus.subscribers {
+		callbacks = append(callbacks, callback)
+	}
+	bus.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(value)
+	}
+}
+
+// batch starts collecting events for a mutation; flush it with defer before taking the lock.
+func (registry *Registry) batch() *eventBatch {
+	return &eventBatch{bus: &registry.events}
+}
+
+// add queues an event for publication.
+func (batch *eventBatch) add(event Event) {
+	batch.events = append(batch.events, event)
+}
+
+// flush publishes all queued events in order.
+func (batch *eventBatch) flush() {
+	for _, event := range batch.events {
+		batch.bus.publish(event)
+	}
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// Subscribe registers a mutation callback on the global registry.
+func Subscribe(callback func(Event)) (unsubscribe func()) {
+	return globalRegistry.Subscribe(callback)
+}
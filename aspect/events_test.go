@@ -0,0 +1,96 @@
+// Package aspect - events_test validates registry mutation events
+package aspect
+
+import (
+	"testing"
+)
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestEvents_PublishedForMutations(t *testing.T) {
+	registry := NewRegistry()
+
+	var events []Event
+	unsubscribe := registry.Subscribe(func(event Event) {
+		events = append(events, event)
+	})
+
+	registry.MustRegister("Func1")
+	registry.RegisterOrGet("Func1") // existing, no event
+	registry.RegisterOrGet("Func2")
+	registry.MustAddAdvice("Func1", Advice{
+		Name:     "logging",
+		Type:     Before,
+		Priority: 100,
+		Handler:  func(ctx *Context) error { return nil },
+	})
+	registry.MustRemoveAdvice("Func1", "logging")
+	_ = registry.Unregister("Func2")
+	_ = registry.Unregister("Missing") // not registered, no event
+	_ = registry.Clear()
+	registry.Freeze()
+
+	expected := []Event{
+		{Type: FunctionRegistered, FunctionName: "Func1"},
+		{Type: FunctionRegistered, FunctionName: "Func2"},
+		{Type: AdviceAdded, FunctionName: "Func1", AdviceName: "logging", AdviceType: Before},
+		{Type: AdviceRemoved, FunctionName: "Func1", AdviceName: "logging", AdviceType: Before},
+		{Type: FunctionUnregistered, FunctionName: "Func2"},
+		{Type: RegistryCleared},
+		{Type: RegistryFrozen},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d: %v", len(expected), len(events), events)
+	}
+	for i, event := range expected {
+		if events[i] != event {
+			t.Errorf("event %d: expected %+v, got %+v", i, event, events[i])
+		}
+	}
+
+	unsubscribe()
+	registry.Freeze()
+	if len(events) != len(expected) {
+		t.Fatal("expected no events after unsubscribe")
+	}
+}
+
+func TestEvents_SubscriberCanReadRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	var adviceCount int
+	registry.Subscribe(func(event Event) {
+		if event.Type == AdviceAdded {
+			// Calling back into the registry must not deadlock
+			adviceCount = registry.GetAdviceCount(event.FunctionName)
+		}
+	})
+
+	registry.MustRegister("Func")
+	registry.MustAddAdvice("Func", Advice{Type: After, Handler: func(ctx *Context) error { return nil }})
+
+	if adviceCount != 1 {
+		t.Fatalf("expected subscriber to observe 1 advice, got %d", adviceCount)
+	}
+}
+
+func TestRegistry_RemoveAdvice(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Func")
+	registry.MustAddAdvice("Func", Advice{Name: "audit", Type: Before, Handler: func(ctx *Context) error { return nil }})
+	registry.MustAddAdvice("Func", Advice{Name: "audit", Type: After, Handler: func(ctx *Context) error { return nil }})
+	registry.MustAddAdvice("Func", Advice{Name: "timing", Type: After, Handler: func(ctx *Context) error { return nil }})
+
+	if err := registry.RemoveAdvice("Func", "audit"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if registry.GetAdviceCount("Func") != 1 {
+		t.Fatalf("expected 1 advice left, got %d", registry.GetAdviceCount("Func"))
+	}
+	if err := registry.RemoveAdvice("Func", "audit"); err == nil {
+		t.Fatal("expected error removing missing advice")
+	}
+	if err := registry.RemoveAdvice("Missing", "timing"); err == nil {
+		t.Fatal("expected error for unregistered function")
+	}
+}
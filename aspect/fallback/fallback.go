@@ -0,0 +1,170 @@
+// Package fallback - fallback provides an aspect replacing failed calls with degraded results
+// from a chain of typed fallback functions
+package fallback
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// UsedKey is the aspect.Context metadata key holding the index of the fallback that produced the results.
+const UsedKey = "fallback.used"
+
+const defaultName = "fallback"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Func computes degraded results for a failed call from its context and error.
+// Returning an error passes the call on to the next fallback in the chain.
+type Func func(ctx *aspect.Context, err error) ([]any, error)
+
+// Config configures a Fallback. Zero values fall back to defaults.
+type Config struct {
+	Name     string // Name of the advice, for runtime switches (default "fallback").
+	Priority int    // Priority of the Around advice; keep it above circuit breaker and timeout advice to catch their errors.
+	Chain    []Func // Chain holds the fallbacks tried in order until one succeeds (required), e.g. Of1(fromCache), Of1(defaults).
+
+	// When selects the errors that trigger the fallbacks (default: every error).
+	When func(err error) bool
+}
+
+// Fallback is an aspect returning degraded results instead of errors.
+type Fallback struct {
+	config Config
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Fallback, filling unset configuration with defaults.
+func New(config Config) *Fallback {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.When == nil {
+		config.When = func(err error) bool { return true }
+	}
+	return &Fallback{config: config}
+}
+
+// Name returns the advice name.
+func (fallback *Fallback) Name() string {
+	return fallback.config.Name
+}
+
+// Advice returns the Around advice applying the fallbacks.
+func (fallback *Fallback) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     fallback.config.Name,
+			Type:     aspect.Around,
+			Priority: fallback.config.Priority,
+			Handler:  fallback.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (fallback *Fallback) Init() error {
+	if len(fallback.config.Chain) == 0 {
+		return fmt.Errorf("fallback '%s': at least one fallback is required", fallback.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; a Fallback holds no state.
+func (fallback *Fallback) Close() error {
+	return nil
+}
+
+// Used returns the index in the chain of the fallback that produced the results, or -1 if none did.
+func Used(ctx *aspect.Context) int {
+	if index, ok := ctx.Metadata[UsedKey].(int); ok {
+		return index
+	}
+	return -1
+}
+
+// Of0 adapts a fallback for functions without arguments.
+func Of0[R any](fn func(err error) (R, error)) Func {
+	return func(ctx *aspect.Context, err error) ([]any, error) {
+		return single(fn(err))
+	}
+}
+
+// Of1 adapts a fallback receiving the original argument of a one-argument function.
+func Of1[A, R any](fn func(a A, err error) (R, error)) Func {
+	return func(ctx *aspect.Context, err error) ([]any, error) {
+		return single(fn(arg[A](ctx, 0), err))
+	}
+}
+
+// Of2 adapts a fallback receiving the original arguments of a two-argument function.
+func Of2[A, B, R any](fn func(a A, b B, err error) (R, error)) Func {
+	return func(ctx *aspect.Context, err error) ([]any, error) {
+		return single(fn(arg[A](ctx, 0), arg[B](ctx, 1), err))
+	}
+}
+
+// Of3 adapts a fallback receiving the original arguments of a three-argument function.
+func Of3[A, B, C, R any](fn func(a A, b B, c C, err error) (R, error)) Func {
+	return func(ctx *aspect.Context, err error) ([]any, error) {
+		return single(fn(arg[A](ctx, 0), arg[B](ctx, 1), arg[C](ctx, 2), err))
+	}
+}
+
+// Value is a fallback always returning value, typically last in the chain.
+func Value[R any](value R) Func {
+	return func(ctx *aspect.Context, err error) ([]any, error) {
+		return []any{value}, nil
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around proceeds and, on a selected error, replaces the outcome with the first successful fallback.
+func (fallback *Fallback) around(ctx *aspect.Context) error {
+	err := ctx.Proceed()
+	if err == nil || !fallback.config.When(err) {
+		return nil
+	}
+
+	errs := []error{err}
+	for index, fn := range fallback.config.Chain {
+		results, fallbackErr := fn(ctx, err)
+		if fallbackErr != nil {
+			errs = append(errs, fallbackErr)
+			continue
+		}
+
+		ctx.Results = results
+		ctx.Error = nil
+		ctx.Degraded = true
+		ctx.Metadata[UsedKey] = index
+		return nil
+	}
+
+	ctx.Error = errors.Join(errs...)
+	return nil
+}
+
+// single wraps a typed fallback outcome into results.
+func single[R any](result R, err error) ([]any, error) {
+	if err != nil {
+		return nil, err
+	}
+	return []any{result}, nil
+}
+
+// arg returns the argument at index as A, or the zero value if absent or of another type.
+func arg[A any](ctx *aspect.Context, index int) A {
+	if index >= len(ctx.Args) {
+		var zero A
+		return zero
+	}
+	value, _ := ctx.Args[index].(A)
+	return value
+}
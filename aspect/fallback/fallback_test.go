@@ -0,0 +1,128 @@
+// Package fallback - fallback_test validates degraded results and fallback chains
+package fallback
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/circuitbreaker"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+var (
+	errUnavailable = errors.New("service unavailable")
+	errNotCached   = errors.New("not cached")
+	errInvalid     = errors.New("invalid input")
+)
+
+// setup registers functions on a fresh global registry and applies the fallback to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestFallback_ChainAndDegradedMarker(t *testing.T) {
+	cached := map[string]string{"u1": "cached u1"}
+	var receivedErr error
+	registry := setup(t, New(Config{
+		Chain: []Func{
+			Of1(func(userID string, err error) (string, error) {
+				receivedErr = err
+				if profile, ok := cached[userID]; ok {
+					return profile, nil
+				}
+				return "", errNotCached
+			}),
+			Value("anonymous"),
+		},
+	}), "FetchProfile")
+
+	var used []int
+	registry.MustAddAdvice("FetchProfile", aspect.Advice{
+		Type: aspect.AfterReturning,
+		Handler: func(ctx *aspect.Context) error {
+			if ctx.Degraded {
+				used = append(used, Used(ctx))
+			}
+			return nil
+		},
+	})
+
+	fetch := aspect.Wrap1RE("FetchProfile", func(userID string) (string, error) {
+		return "", errUnavailable
+	})
+
+	if profile, err := fetch("u1"); err != nil || profile != "cached u1" {
+		t.Fatalf("expected first fallback result, got (%q, %v)", profile, err)
+	}
+	if !errors.Is(receivedErr, errUnavailable) {
+		t.Errorf("expected fallback to receive the original error, got %v", receivedErr)
+	}
+	if profile, err := fetch("u2"); err != nil || profile != "anonymous" {
+		t.Fatalf("expected chained fallback result, got (%q, %v)", profile, err)
+	}
+	if len(used) != 2 || used[0] != 0 || used[1] != 1 {
+		t.Fatalf("expected 2 degraded responses from fallbacks [0 1], got %v", used)
+	}
+}
+
+func TestFallback_WhenAndExhaustedChain(t *testing.T) {
+	setup(t, New(Config{
+		When: func(err error) bool { return !errors.Is(err, errInvalid) },
+		Chain: []Func{Of2(func(a, b int, err error) (int, error) {
+			return 0, errNotCached
+		})},
+	}), "Validate")
+
+	failure := errInvalid
+	validate := aspect.Wrap2RE("Validate", func(a, b int) (int, error) { return 0, failure })
+
+	if _, err := validate(1, 2); !errors.Is(err, errInvalid) || errors.Is(err, errNotCached) {
+		t.Fatalf("expected unselected error to pass through, got %v", err)
+	}
+
+	failure = errUnavailable
+	if _, err := validate(1, 2); !errors.Is(err, errUnavailable) || !errors.Is(err, errNotCached) {
+		t.Fatalf("expected original and fallback errors when the chain is exhausted, got %v", err)
+	}
+}
+
+func TestFallback_CatchesOpenCircuit(t *testing.T) {
+	registry := setup(t, New(Config{
+		Priority: 200,
+		When:     func(err error) bool { return errors.Is(err, circuitbreaker.ErrCircuitOpen) },
+		Chain:    []Func{Value("degraded")},
+	}), "CallService")
+	registry.MustApply(aspect.On("CallService"), circuitbreaker.New(circuitbreaker.Config{
+		Priority: 100,
+		Policy:   circuitbreaker.ConsecutiveFailures(1),
+	}))
+
+	call := aspect.Wrap0RE("CallService", func() (string, error) { return "", errUnavailable })
+
+	if _, err := call(); !errors.Is(err, errUnavailable) {
+		t.Fatalf("expected the failure itself to pass through, got %v", err)
+	}
+	if result, err := call(); err != nil || result != "degraded" {
+		t.Fatalf("expected open circuit to be degraded, got (%q, %v)", result, err)
+	}
+}
+
+func TestFallback_InitRequiresChain(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Fatal("expected error without fallbacks")
+	}
+}
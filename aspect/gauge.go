@@ -0,0 +1,97 @@
+// Package aspect - gauge publishes live values (e.g. active or queued calls) of aspects through the registry
+package aspect
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Gauge is a live value sampled on read, identified by Name and Key.
+type Gauge struct {
+	Name  string       // Name identifies the measured quantity, e.g. "bulkhead.active".
+	Key   string       // Key distinguishes gauges sharing a name, e.g. a function name.
+	Value func() int64 // Value samples the gauge; it must be cheap and safe for concurrent use.
+}
+
+// gaugeKey identifies a registered gauge.
+type gaugeKey struct {
+	name, key string
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// RegisterGauge publishes a gauge. Returns error if the name is empty, the value is nil,
+// or a gauge with the same name and key is already registered.
+func (registry *Registry) RegisterGauge(gauge Gauge) error {
+	if gauge.Name == "" || gauge.Value == nil {
+		return errors.New("gauge name and value are required")
+	}
+
+	registry.gaugeMu.Lock()
+	defer registry.gaugeMu.Unlock()
+
+	id := gaugeKey{gauge.Name, gauge.Key}
+	if _, exists := registry.gauges[id]; exists {
+		return fmt.Errorf("gauge '%s' with key '%s' already registered", gauge.Name, gauge.Key)
+	}
+	registry.gauges[id] = gauge
+	return nil
+}
+
+// UnregisterGauge removes a gauge; removing an unknown gauge is a no-op.
+func (registry *Registry) UnregisterGauge(name, key string) {
+	registry.gaugeMu.Lock()
+	defer registry.gaugeMu.Unlock()
+	delete(registry.gauges, gaugeKey{name, key})
+}
+
+// Gauges returns the registered gauges sorted by name and key.
+func (registry *Registry) Gauges() []Gauge {
+	registry.gaugeMu.RLock()
+	gauges := make([]Gauge, 0, len(registry.gauges))
+	for _, gauge := range registry.gauges {
+		gauges = append(gauges, gauge)
+	}
+	registry.gaugeMu.RUnlock()
+
+	sort.Slice(gauges, func(i, j int) bool {
+		if gauges[i].Name != gauges[j].Name {
+			return gauges[i].Name < gauges[j].Name
+		}
+		return gauges[i].Key < gauges[j].Key
+	})
+	return gauges
+}
+
+// GaugeValue samples the gauge with the given name and key.
+// Returns false if no such gauge is registered.
+func (registry *Registry) GaugeValue(name, key string) (int64, bool) {
+	registry.gaugeMu.RLock()
+	gauge, exists := registry.gauges[gaugeKey{name, key}]
+	registry.gaugeMu.RUnlock()
+
+	if !exists {
+		return 0, false
+	}
+	return gauge.Value(), true
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// RegisterGauge publishes a gauge in the global registry.
+func RegisterGauge(gauge Gauge) error {
+	return globalRegistry.RegisterGauge(gauge)
+}
+
+// Gauges returns the gauges of the global registry.
+func Gauges() []Gauge {
+	return globalRegistry.Gauges()
+}
+
+// GaugeValue samples a gauge of the global registry.
+func GaugeValue(name, key string) (int64, bool) {
+	return globalRegistry.GaugeValue(name, key)
+}
@@ -0,0 +1,66 @@
+// Package aspect - gauge_test validates gauge publication through the registry
+package aspect
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// gaugePublisher is a registry-aware aspect publishing a gauge on attach.
+type gaugePublisher struct {
+	failureCounter
+	value atomic.Int64
+}
+
+func (publisher *gaugePublisher) Attach(registry *Registry) {
+	_ = registry.RegisterGauge(Gauge{Name: "publisher.value", Value: publisher.value.Load})
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestGauge_RegisterAndRead(t *testing.T) {
+	registry := NewRegistry()
+
+	var active atomic.Int64
+	if err := registry.RegisterGauge(Gauge{Name: "pool.active", Key: "orders", Value: active.Load}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = registry.RegisterGauge(Gauge{Name: "pool.active", Key: "billing", Value: func() int64 { return 7 }})
+
+	if err := registry.RegisterGauge(Gauge{Name: "pool.active", Key: "orders", Value: active.Load}); err == nil {
+		t.Error("expected error for duplicate gauge")
+	}
+	if err := registry.RegisterGauge(Gauge{Name: "pool.active"}); err == nil {
+		t.Error("expected error for gauge without value")
+	}
+
+	active.Store(3)
+	if value, ok := registry.GaugeValue("pool.active", "orders"); !ok || value != 3 {
+		t.Fatalf("expected live value 3, got %d (found %v)", value, ok)
+	}
+
+	gauges := registry.Gauges()
+	if len(gauges) != 2 || gauges[0].Key != "billing" || gauges[1].Key != "orders" {
+		t.Fatalf("expected gauges sorted by key, got %+v", gauges)
+	}
+
+	registry.UnregisterGauge("pool.active", "orders")
+	if _, ok := registry.GaugeValue("pool.active", "orders"); ok {
+		t.Error("expected unregistered gauge to be gone")
+	}
+}
+
+func TestGauge_RegistryAwareAspect(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("FetchUser")
+
+	publisher := &gaugePublisher{}
+	publisher.value.Store(42)
+	registry.MustApply(On("FetchUser"), publisher)
+
+	if value, ok := registry.GaugeValue("publisher.value", ""); !ok || value != 42 {
+		t.Fatalf("expected attached aspect to publish its gauge, got %d (found %v)", value, ok)
+	}
+}
@@ -0,0 +1,112 @@
+// Package hedge - delay defines how long a call runs before a hedged attempt is launched
+package hedge
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// DelayPolicy decides the hedging delay of a single function from its observed latency.
+// Implementations must be safe for concurrent use.
+type DelayPolicy interface {
+	// Delay returns the time to wait for an attempt before launching the next one.
+	Delay() time.Duration
+	// Observe records the latency of a successful call, from its start to its first successful attempt.
+	Observe(latency time.Duration)
+}
+
+// DelayFactory creates the DelayPolicy of a function.
+type DelayFactory func() DelayPolicy
+
+// fixedDelay always waits the same time.
+type fixedDelay time.Duration
+
+// validator is implemented by the built-in policies to reject unusable settings in Init.
+type validator interface {
+	validate() error
+}
+
+// percentileDelay waits for a latency percentile of the last successful calls.
+type percentileDelay struct {
+	mu         sync.Mutex
+	percentile float64
+	initial    time.Duration
+	minSamples int
+	samples    []time.Duration // samples is a ring buffer of the last latencies, oldest at next.
+	sorted     []time.Duration // sorted holds the same latencies in increasing order.
+	next       int
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Fixed hedges after a constant delay.
+func Fixed(delay time.Duration) DelayFactory {
+	return func() DelayPolicy {
+		return fixedDelay(delay)
+	}
+}
+
+// Percentile hedges once an attempt is slower than the given percentile (0-1, e.g. 0.95) of the
+// last window successful call latencies, using initial until minSamples latencies were observed.
+// minSamples must not exceed window.
+func Percentile(percentile float64, initial time.Duration, window, minSamples int) DelayFactory {
+	return func() DelayPolicy {
+		return &percentileDelay{
+			percentile: math.Min(math.Max(percentile, 0), 1),
+			initial:    initial,
+			minSamples: max(minSamples, 1),
+			samples:    make([]time.Duration, 0, max(window, 1)),
+			sorted:     make([]time.Duration, 0, max(window, 1)),
+		}
+	}
+}
+
+// Delay implements DelayPolicy.
+func (delay fixedDelay) Delay() time.Duration {
+	return time.Duration(delay)
+}
+
+// Observe implements DelayPolicy.
+func (delay fixedDelay) Observe(time.Duration) {}
+
+// Delay implements DelayPolicy.
+func (delay *percentileDelay) Delay() time.Duration {
+	delay.mu.Lock()
+	defer delay.mu.Unlock()
+
+	if len(delay.sorted) < delay.minSamples {
+		return delay.initial
+	}
+	index := int(math.Ceil(delay.percentile*float64(len(delay.sorted)))) - 1
+	return delay.sorted[max(index, 0)]
+}
+
+// Observe implements DelayPolicy; the sorted latencies are kept up to date so that Delay stays cheap.
+func (delay *percentileDelay) Observe(latency time.Duration) {
+	delay.mu.Lock()
+	defer delay.mu.Unlock()
+
+	if len(delay.samples) < cap(delay.samples) {
+		delay.samples = append(delay.samples, latency)
+	} else {
+		evicted, _ := slices.BinarySearch(delay.sorted, delay.samples[delay.next])
+		delay.sorted = slices.Delete(delay.sorted, evicted, evicted+1)
+		delay.samples[delay.next] = latency
+		delay.next = (delay.next + 1) % len(delay.samples)
+	}
+	position, _ := slices.BinarySearch(delay.sorted, latency)
+	delay.sorted = slices.Insert(delay.sorted, position, latency)
+}
+
+// validate rejects a window that can never hold minSamples latencies.
+func (delay *percentileDelay) validate() error {
+	if delay.minSamples > cap(delay.samples) {
+		return fmt.Errorf("min samples %d exceed the window of %d latencies", delay.minSamples, cap(delay.samples))
+	}
+	return nil
+}
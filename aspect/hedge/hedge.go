@@ -0,0 +1,245 @@
+// Package hedge - hedge provides an aspect launching extra attempts of slow idempotent calls
+// and returning the first success, to cut tail latency
+package hedge
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	defaultName      = "hedge"
+	defaultMaxHedges = 1
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Stats are the hedging counters of a single function.
+type Stats struct {
+	Calls  uint64 // Calls are the hedged function's invocations.
+	Hedged uint64 // Hedged are calls that launched at least one extra attempt.
+	Wins   uint64 // Wins are calls whose result came from an extra attempt rather than the first one.
+}
+
+// Config configures a Hedger. Zero values fall back to defaults.
+type Config struct {
+	Name      string       // Name of the advice, for runtime switches (default "hedge").
+	Priority  int          // Priority of the Around advice.
+	Delay     DelayFactory // Delay decides when to launch the next attempt (required), e.g. Fixed(50ms) or Percentile(0.95, ...).
+	MaxHedges int          // MaxHedges is the number of extra attempts per call (default 1).
+}
+
+// Hedger is an aspect racing attempts of the functions it is applied to. Only apply it to
+// idempotent functions: losing attempts keep running until they observe their cancelled
+// context.Context (passed as first argument to context-accepting targets).
+type Hedger struct {
+	config Config
+
+	mu        sync.Mutex
+	functions map[string]*functionState
+}
+
+// functionState holds the delay policy and live Stats of a function.
+type functionState struct {
+	policy              DelayPolicy
+	calls, hedged, wins atomic.Uint64
+}
+
+// attempt is the outcome of a single attempt.
+type attempt struct {
+	index      int
+	fork       *aspect.Context
+	panicValue any
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Hedger, filling unset configuration with defaults.
+func New(config Config) *Hedger {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.MaxHedges <= 0 {
+		config.MaxHedges = defaultMaxHedges
+	}
+	return &Hedger{config: config, functions: make(map[string]*functionState)}
+}
+
+// Name returns the advice name.
+func (hedger *Hedger) Name() string {
+	return hedger.config.Name
+}
+
+// Advice returns the Around advice racing attempts.
+func (hedger *Hedger) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     hedger.config.Name,
+			Type:     aspect.Around,
+			Priority: hedger.config.Priority,
+			Handler:  hedger.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (hedger *Hedger) Init() error {
+	if hedger.config.Delay == nil {
+		return fmt.Errorf("hedger '%s': delay is required", hedger.config.Name)
+	}
+	if probe, ok := hedger.config.Delay().(validator); ok {
+		if err := probe.validate(); err != nil {
+			return fmt.Errorf("hedger '%s': %w", hedger.config.Name, err)
+		}
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; losing attempts are already cancelled, and Registry.Shutdown waits for them.
+func (hedger *Hedger) Close() error {
+	return nil
+}
+
+// Stats returns the counters of a function.
+func (hedger *Hedger) Stats(functionName string) Stats {
+	state := hedger.function(functionName)
+	return Stats{
+		Calls:  state.calls.Load(),
+		Hedged: state.hedged.Load(),
+		Wins:   state.wins.Load(),
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around launches the first attempt, then one more each time the delay elapses without a result,
+// and keeps the first success (or the first failure if every attempt fails).
+func (hedger *Hedger) around(ctx *aspect.Context) error {
+	state := hedger.function(ctx.FunctionName)
+	state.calls.Add(1)
+	start := time.Now()
+	delay := state.policy.Delay()
+	parent := ctx.Context()
+
+	outcomes := make(chan attempt, hedger.config.MaxHedges+1)
+	var cancels []context.CancelFunc
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	// launch starts an attempt counted as in flight until its goroutine returns, so Shutdown
+	// waits for losing attempts too; none starts once the registry is shutting down
+	launch := func(index int) bool {
+		fork := ctx.Fork()
+		release, err := fork.Hold()
+		if err != nil {
+			return false
+		}
+		attemptCtx, cancel := context.WithCancel(parent)
+		cancels = append(cancels, cancel)
+		if len(fork.Args) > 0 {
+			if _, acceptsContext := fork.Args[0].(context.Context); acceptsContext {
+				fork.Args[0] = attemptCtx
+			}
+		}
+
+		go func() {
+			defer release()
+			defer func() {
+				outcomes <- attempt{index: index, fork: fork, panicValue: recover()}
+			}()
+			_ = fork.Proceed()
+		}()
+		return true
+	}
+
+	if !launch(0) {
+		_ = ctx.Proceed()
+		return nil
+	}
+	launched, pending := 1, 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	var firstFailure *attempt
+	for pending > 0 {
+		hedgeTimer := timer.C
+		if launched > hedger.config.MaxHedges {
+			hedgeTimer = nil
+		}
+
+		select {
+		case <-hedgeTimer:
+			if !launch(launched) {
+				launched = hedger.config.MaxHedges + 1 // Shutting down: let the running attempts finish
+				continue
+			}
+			if launched == 1 {
+				state.hedged.Add(1)
+			}
+			launched++
+			pending++
+			timer.Reset(delay)
+
+		case outcome := <-outcomes:
+			pending--
+			if outcome.panicValue != nil {
+				panic(outcome.panicValue)
+			}
+			if outcome.fork.Error == nil {
+				// Timed from the start of the call: a slow first attempt beaten by a hedge still
+				// raises the observed latency above the delay, instead of pulling the percentile down
+				state.policy.Observe(time.Since(start))
+				if outcome.index > 0 {
+					state.wins.Add(1)
+				}
+				adopt(ctx, outcome.fork)
+				return nil
+			}
+			if firstFailure == nil {
+				firstFailure = &outcome
+			}
+
+		case <-parent.Done():
+			ctx.Error = context.Cause(parent)
+			ctx.Skipped = true
+			return nil
+		}
+	}
+
+	adopt(ctx, firstFailure.fork)
+	return nil
+}
+
+// function returns the state of a function, creating it on first use.
+func (hedger *Hedger) function(functionName string) *functionState {
+	hedger.mu.Lock()
+	defer hedger.mu.Unlock()
+
+	state, exists := hedger.functions[functionName]
+	if !exists {
+		state = &functionState{policy: hedger.config.Delay()}
+		hedger.functions[functionName] = state
+	}
+	return state
+}
+
+// adopt copies the outcome of an attempt into the call's context.
+func adopt(ctx *aspect.Context, fork *aspect.Context) {
+	ctx.Results = fork.Results
+	ctx.Error = fork.Error
+	ctx.Skipped = fork.Skipped
+	ctx.Degraded = fork.Degraded
+	for key, value := range fork.Metadata {
+		ctx.Metadata[key] = value
+	}
+}
@@ -0,0 +1,195 @@
+// Package hedge - hedge_test validates hedged attempts, cancellation and delay policies
+package hedge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies the hedger to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// recordingDelay is a fixed delay policy recording the observed latencies.
+type recordingDelay struct {
+	delay    time.Duration
+	observed chan time.Duration
+}
+
+func (policy *recordingDelay) Delay() time.Duration { return policy.delay }
+
+func (policy *recordingDelay) Observe(latency time.Duration) { policy.observed <- latency }
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestHedger_HedgeWinsAndLoserIsCancelled(t *testing.T) {
+	hedger := New(Config{Delay: Fixed(10 * time.Millisecond)})
+	setup(t, hedger, "Read")
+
+	var attempts atomic.Int32
+	loserCancelled := make(chan struct{})
+	read := aspect.Wrap2RE("Read", func(ctx context.Context, key string) (string, error) {
+		if attempts.Add(1) == 1 {
+			// The first attempt stalls until it is cancelled
+			<-ctx.Done()
+			close(loserCancelled)
+			return "", ctx.Err()
+		}
+		return "value of " + key, nil
+	})
+
+	if result, err := read(context.Background(), "k"); err != nil || result != "value of k" {
+		t.Fatalf("expected hedge result, got (%q, %v)", result, err)
+	}
+
+	select {
+	case <-loserCancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected losing attempt to be cancelled")
+	}
+
+	if stats := hedger.Stats("Read"); stats.Calls != 1 || stats.Hedged != 1 || stats.Wins != 1 {
+		t.Fatalf("expected 1 hedged call won by the hedge, got %+v", stats)
+	}
+}
+
+func TestHedger_ShutdownWaitsForLosingAttempts(t *testing.T) {
+	registry := setup(t, New(Config{Delay: Fixed(10 * time.Millisecond)}), "Read")
+
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	read := aspect.Wrap1RE("Read", func(key string) (string, error) {
+		if attempts.Add(1) == 1 {
+			<-release // Ignores its context: the losing attempt keeps running
+		}
+		return "value of " + key, nil
+	})
+	if _, err := read("k"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	shutdown := make(chan error)
+	go func() { shutdown <- registry.Shutdown(context.Background()) }()
+	select {
+	case <-shutdown:
+		t.Fatal("expected Shutdown to wait for the losing attempt")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestHedger_FastCallsAreNotHedged(t *testing.T) {
+	hedger := New(Config{Delay: Fixed(time.Second), MaxHedges: 2})
+	setup(t, hedger, "Fast")
+
+	var attempts atomic.Int32
+	fast := aspect.Wrap0RE("Fast", func() (int, error) {
+		attempts.Add(1)
+		return 7, nil
+	})
+
+	if result, err := fast(); err != nil || result != 7 {
+		t.Fatalf("expected (7, nil), got (%d, %v)", result, err)
+	}
+	if attempts.Load() != 1 || hedger.Stats("Fast").Hedged != 0 {
+		t.Fatalf("expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestHedger_AllAttemptsFail(t *testing.T) {
+	errBackend := errors.New("backend down")
+	hedger := New(Config{Delay: Fixed(time.Millisecond), MaxHedges: 2})
+	setup(t, hedger, "Failing")
+
+	var attempts atomic.Int32
+	failing := aspect.Wrap0RE("Failing", func() (int, error) {
+		attempts.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return 0, errBackend
+	})
+
+	if _, err := failing(); !errors.Is(err, errBackend) {
+		t.Fatalf("expected backend error, got %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected the first attempt plus 2 hedges, got %d", attempts.Load())
+	}
+}
+
+func TestPercentile_Delay(t *testing.T) {
+	policy := Percentile(0.9, 50*time.Millisecond, 10, 5)()
+	if delay := policy.Delay(); delay != 50*time.Millisecond {
+		t.Fatalf("expected initial delay before enough samples, got %v", delay)
+	}
+
+	for i := 1; i <= 10; i++ {
+		policy.Observe(time.Duration(i) * time.Millisecond)
+	}
+	if delay := policy.Delay(); delay != 9*time.Millisecond {
+		t.Fatalf("expected p90 of 1..10ms to be 9ms, got %v", delay)
+	}
+
+	// Older samples leave the window
+	for i := 0; i < 10; i++ {
+		policy.Observe(100 * time.Millisecond)
+	}
+	if delay := policy.Delay(); delay != 100*time.Millisecond {
+		t.Fatalf("expected window to hold only recent samples, got %v", delay)
+	}
+}
+
+func TestHedger_ObservesLatencyFromCallStart(t *testing.T) {
+	policy := &recordingDelay{delay: 10 * time.Millisecond, observed: make(chan time.Duration, 1)}
+	setup(t, New(Config{Delay: func() DelayPolicy { return policy }}), "Read")
+
+	var attempts atomic.Int32
+	read := aspect.Wrap1RE("Read", func(ctx context.Context) (int, error) {
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}
+		return 1, nil
+	})
+	if _, err := read(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// The hedge answered at once, but the call waited for the delay before launching it
+	if latency := <-policy.observed; latency < policy.delay {
+		t.Fatalf("expected the observed latency to include the delay, got %v", latency)
+	}
+}
+
+func TestHedger_InitValidation(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Error("expected error without delay")
+	}
+	if err := New(Config{Delay: Percentile(0.95, time.Millisecond, 10, 20)}).Init(); err == nil {
+		t.Error("expected error for more min samples than the window holds")
+	}
+	if err := New(Config{Delay: Percentile(0.95, time.Millisecond, 10, 10)}).Init(); err != nil {
+		t.Errorf("expected a valid percentile delay, got %v", err)
+	}
+}
@@ -433,3 +433,136 @@ func TestIntegration_MetadataPassingBetweenAdvice(t *testing.T) {
 
 	Clear()
 }
+
+func TestIntegration_AroundProceed(t *testing.T) {
+	Clear()
+
+	_ = Register("ProceedTest")
+
+	var executionOrder []string
+
+	// Outer Around: proceeds twice, re-invoking inner advice and target
+	_ = AddAdvice("ProceedTest", Advice{
+		Type:     Around,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			executionOrder = append(executionOrder, "outer-start")
+			if err := ctx.Proceed(); err != nil {
+				executionOrder = append(executionOrder, "outer-retry")
+				_ = ctx.Proceed()
+			}
+			executionOrder = append(executionOrder, "outer-end")
+			return nil
+		},
+	})
+
+	// Inner Around: legacy style, never proceeds explicitly
+	_ = AddAdvice("ProceedTest", Advice{
+		Type:     Around,
+		Priority: 50,
+		Handler: func(ctx *Context) error {
+			executionOrder = append(executionOrder, "inner")
+			return nil
+		},
+	})
+
+	attempts := 0
+	targetFunc := func(x int) (int, error) {
+		attempts++
+		executionOrder = append(executionOrder, "target")
+		if attempts == 1 {
+			return 0, errors.New("transient")
+		}
+		return x * 2, nil
+	}
+
+	wrapped := Wrap1RE("ProceedTest", targetFunc)
+	result, err := wrapped(5)
+
+	if err != nil || result != 10 {
+		t.Fatalf("expected (10, nil), got (%d, %v)", result, err)
+	}
+
+	expectedOrder := []string{"outer-start", "inner", "target", "outer-retry", "inner", "target", "outer-end"}
+	if len(executionOrder) != len(expectedOrder) {
+		t.Fatalf("expected %v, got %v", expectedOrder, executionOrder)
+	}
+	for i, step := range expectedOrder {
+		if executionOrder[i] != step {
+			t.Errorf("step %d: expected %s, got %s", i, step, executionOrder[i])
+		}
+	}
+
+	// Proceed outside Around advice is rejected
+	if err := NewContext("ProceedTest").Proceed(); !errors.Is(err, ErrProceedUnavailable) {
+		t.Errorf("expected ErrProceedUnavailable, got %v", err)
+	}
+
+	Clear()
+}
+
+func TestIntegration_AroundReplacesArgsAndForks(t *testing.T) {
+	Clear()
+
+	_ = Register("ForkTest")
+
+	var forked *Context
+	_ = AddAdvice("ForkTest", Advice{
+		Type:     Around,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			forked = ctx.Fork()
+			ctx.Args[0] = ctx.Args[0].(int) + 1
+			return ctx.Proceed()
+		},
+	})
+
+	wrapped := Wrap1RE("ForkTest", func(x int) (int, error) { return x * 2, nil })
+	if result, err := wrapped(5); err != nil || result != 12 {
+		t.Fatalf("expected target to see replaced argument (12, nil), got (%d, %v)", result, err)
+	}
+
+	// The fork keeps the original arguments and proceeds independently after the call returned
+	done := make(chan error)
+	go func() { done <- forked.Proceed() }()
+	if err := <-done; err != nil || forked.GetResult(0) != 10 {
+		t.Fatalf("expected fork to produce (10, nil), got (%v, %v)", forked.GetResult(0), err)
+	}
+
+	Clear()
+}
+
+func TestIntegration_SkippedShortCircuitsAround(t *testing.T) {
+	Clear()
+
+	_ = Register("SkipTest")
+
+	innerRan := false
+	_ = AddAdvice("SkipTest", Advice{
+		Type:     Around,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			ctx.SetResult(0, "cached")
+			ctx.Skipped = true
+			return nil
+		},
+	})
+	_ = AddAdvice("SkipTest", Advice{
+		Type:     Around,
+		Priority: 50,
+		Handler: func(ctx *Context) error {
+			innerRan = true
+			return nil
+		},
+	})
+
+	wrapped := Wrap0R("SkipTest", func() string { return "computed" })
+	if result := wrapped(); result != "cached" {
+		t.Fatalf("expected cached, got %s", result)
+	}
+	if innerRan {
+		t.Error("expected lower-priority Around advice to be skipped")
+	}
+
+	Clear()
+}
@@ -0,0 +1,229 @@
+// Package logging - logging provides an aspect emitting log/slog records on entry, exit, error
+// and panic of the functions it is applied to
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// InvocationIDKey is the aspect.Context metadata key holding the invocation ID of a call.
+const InvocationIDKey = "logging.invocation_id"
+
+// Record messages, one per outcome.
+const (
+	MessageEntry = "call started"
+	MessageExit  = "call finished"
+	MessageError = "call failed"
+	MessagePanic = "call panicked"
+)
+
+const defaultName = "logging"
+
+var (
+	// invocationPrefix distinguishes invocation IDs of different processes.
+	invocationPrefix = fmt.Sprintf("%08x", rand.Uint32())
+	// invocationCounter numbers the invocations of this process.
+	invocationCounter atomic.Uint64
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Levels selects the record level of each outcome.
+type Levels struct {
+	Entry slog.Level // Entry is the level of "call started" records (default Debug).
+	Exit  slog.Level // Exit is the level of "call finished" records (default Info).
+	Error slog.Level // Error is the level of "call failed" records (default Error).
+	Panic slog.Level // Panic is the level of "call panicked" records (default Error).
+}
+
+// Config configures a Logger. Zero values fall back to defaults.
+type Config struct {
+	Name     string       // Name of the advice, for runtime switches (default "logging").
+	Priority int          // Priority of the Around advice; keep it highest to time the whole chain.
+	Logger   *slog.Logger // Logger receives the records (default slog.Default()).
+	Levels   *Levels      // Levels selects the level by outcome (default Debug, Info, Error, Error).
+	Args     Renderer     // Args renders the arguments on entry and failure (default Values).
+	Results  Renderer     // Results renders the results on exit (default Values).
+
+	// Unredacted renders the raw arguments and results instead of their aspect.Redacted view.
+	Unredacted bool
+
+	// Sample selects the successful calls whose entry and exit are logged (default: every call).
+	// Errors and panics are always logged. See Every and Rate.
+	Sample func(ctx *aspect.Context) bool
+}
+
+// Logger is an aspect logging the calls of the functions it is applied to. Apply it to a
+// single function with aspect.On(name) or to many at once with any other pointcut.
+type Logger struct {
+	config Config
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Logger, filling unset configuration with defaults.
+func New(config Config) *Logger {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+	if config.Levels == nil {
+		config.Levels = &Levels{Entry: slog.LevelDebug, Exit: slog.LevelInfo, Error: slog.LevelError, Panic: slog.LevelError}
+	}
+	if config.Args == nil {
+		config.Args = Values
+	}
+	if config.Results == nil {
+		config.Results = Values
+	}
+	if config.Sample == nil {
+		config.Sample = func(ctx *aspect.Context) bool { return true }
+	}
+	return &Logger{config: config}
+}
+
+// Name returns the advice name.
+func (logger *Logger) Name() string {
+	return logger.config.Name
+}
+
+// Advice returns the Around advice logging the call.
+func (logger *Logger) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     logger.config.Name,
+			Type:     aspect.Around,
+			Priority: logger.config.Priority,
+			Handler:  logger.around,
+		},
+	}
+}
+
+// Init implements aspect.Aspect; every setting has a default.
+func (logger *Logger) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; the slog.Logger is owned by the caller.
+func (logger *Logger) Close() error {
+	return nil
+}
+
+// InvocationID returns the invocation ID of a call, or "" if no Logger has seen it.
+func InvocationID(ctx *aspect.Context) string {
+	id, _ := ctx.Metadata[InvocationIDKey].(string)
+	return id
+}
+
+// Every samples one call in n (n <= 1 samples every call).
+func Every(n int) func(ctx *aspect.Context) bool {
+	var calls atomic.Uint64
+	return func(ctx *aspect.Context) bool {
+		return n <= 1 || (calls.Add(1)-1)%uint64(n) == 0
+	}
+}
+
+// Rate samples each call with the given probability (0-1).
+func Rate(probability float64) func(ctx *aspect.Context) bool {
+	return func(ctx *aspect.Context) bool {
+		return rand.Float64() < probability
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around logs the entry of a sampled call, proceeds, and logs its exit, error or panic.
+func (logger *Logger) around(ctx *aspect.Context) error {
+	id := InvocationID(ctx)
+	if id == "" {
+		id = fmt.Sprintf("%s-%x", invocationPrefix, invocationCounter.Add(1))
+		ctx.Metadata[InvocationIDKey] = id
+	}
+	parent := ctx.Context()
+	levels := logger.config.Levels
+	sampled := logger.config.Sample(ctx)
+
+	if sampled {
+		logger.log(parent, levels.Entry, MessageEntry, ctx, id, 0, func(attrs []slog.Attr) []slog.Attr {
+			return appendRendered(attrs, "args", logger.config.Args, logger.values(ctx).Args)
+		})
+	}
+
+	start := time.Now()
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			logger.log(parent, levels.Panic, MessagePanic, ctx, id, time.Since(start), func(attrs []slog.Attr) []slog.Attr {
+				attrs = appendRendered(attrs, "args", logger.config.Args, logger.values(ctx).Args)
+				return append(attrs, slog.Any("panic", panicValue))
+			})
+			panic(panicValue)
+		}
+	}()
+
+	_ = ctx.Proceed()
+	elapsed := time.Since(start)
+
+	if ctx.Error != nil {
+		logger.log(parent, levels.Error, MessageError, ctx, id, elapsed, func(attrs []slog.Attr) []slog.Attr {
+			attrs = appendRendered(attrs, "args", logger.config.Args, logger.values(ctx).Args)
+			return append(attrs, slog.Any("error", ctx.Error))
+		})
+		return nil
+	}
+	if sampled {
+		logger.log(parent, levels.Exit, MessageExit, ctx, id, elapsed, func(attrs []slog.Attr) []slog.Attr {
+			return appendRendered(attrs, "results", logger.config.Results, logger.values(ctx).Results)
+		})
+	}
+	return nil
+}
+
+// log emits a record with the common attributes, rendering the rest only if the level is enabled.
+func (logger *Logger) log(parent context.Context, level slog.Level, message string, ctx *aspect.Context, id string, elapsed time.Duration, details func([]slog.Attr) []slog.Attr) {
+	if !logger.config.Logger.Enabled(parent, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("function", ctx.FunctionName),
+		slog.String("invocation_id", id),
+	}
+	if message != MessageEntry {
+		attrs = append(attrs, slog.Duration("duration", elapsed))
+	}
+	if ctx.Skipped {
+		attrs = append(attrs, slog.Bool("skipped", true))
+	}
+	if ctx.Degraded {
+		attrs = append(attrs, slog.Bool("degraded", true))
+	}
+	logger.config.Logger.LogAttrs(parent, level, message, details(attrs)...)
+}
+
+// values returns the arguments and results to render, redacted unless configured otherwise.
+func (logger *Logger) values(ctx *aspect.Context) aspect.RedactedValues {
+	if logger.config.Unredacted {
+		return aspect.RedactedValues{Args: ctx.Args, Results: ctx.Results}
+	}
+	return aspect.Redacted(ctx)
+}
+
+// appendRendered appends the rendered values under key, unless the renderer omits them.
+func appendRendered(attrs []slog.Attr, key string, render Renderer, values []any) []slog.Attr {
+	value := render(values)
+	if value.Kind() == slog.KindAny && value.Any() == nil {
+		return attrs
+	}
+	return append(attrs, slog.Attr{Key: key, Value: value})
+}
@@ -0,0 +1,192 @@
+// Package logging - logging_test validates the records emitted for each call outcome
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// recorder is a slog.Handler keeping every record.
+type recorder struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (recorder *recorder) Enabled(context.Context, slog.Level) bool { return true }
+func (recorder *recorder) WithAttrs([]slog.Attr) slog.Handler       { return recorder }
+func (recorder *recorder) WithGroup(string) slog.Handler            { return recorder }
+
+func (recorder *recorder) Handle(_ context.Context, record slog.Record) error {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.records = append(recorder.records, record)
+	return nil
+}
+
+// attrs returns the attributes of a record by key.
+func attrs(record slog.Record) map[string]slog.Value {
+	values := make(map[string]slog.Value)
+	record.Attrs(func(attr slog.Attr) bool {
+		values[attr.Key] = attr.Value
+		return true
+	})
+	return values
+}
+
+// setup registers functions on a fresh global registry and applies a Logger writing to a recorder.
+func setup(t *testing.T, config Config, names ...string) *recorder {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	records := &recorder{}
+	config.Logger = slog.New(records)
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), New(config))
+	return records
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestLogger_EntryAndExit(t *testing.T) {
+	records := setup(t, Config{}, "Add")
+	add := aspect.Wrap2R("Add", func(a, b int) int { return a + b })
+
+	if add(2, 3) != 5 {
+		t.Fatal("expected logging to keep the result")
+	}
+	if len(records.records) != 2 {
+		t.Fatalf("expected entry and exit records, got %d", len(records.records))
+	}
+
+	entry, exit := records.records[0], records.records[1]
+	if entry.Message != MessageEntry || entry.Level != slog.LevelDebug {
+		t.Errorf("unexpected entry record %q at %v", entry.Message, entry.Level)
+	}
+	if exit.Message != MessageExit || exit.Level != slog.LevelInfo {
+		t.Errorf("unexpected exit record %q at %v", exit.Message, exit.Level)
+	}
+
+	entryAttrs, exitAttrs := attrs(entry), attrs(exit)
+	if entryAttrs["function"].String() != "Add" || entryAttrs["invocation_id"].String() == "" {
+		t.Errorf("expected function and invocation ID, got %v", entryAttrs)
+	}
+	if entryAttrs["invocation_id"].String() != exitAttrs["invocation_id"].String() {
+		t.Error("expected entry and exit to share the invocation ID")
+	}
+	if args := entryAttrs["args"].Group(); len(args) != 2 || args[1].Value.Int64() != 3 {
+		t.Errorf("expected rendered arguments, got %v", entryAttrs["args"])
+	}
+	if results := exitAttrs["results"].Group(); len(results) != 1 || results[0].Value.Int64() != 5 {
+		t.Errorf("expected rendered results, got %v", exitAttrs["results"])
+	}
+	if _, ok := exitAttrs["duration"]; !ok {
+		t.Error("expected exit record to carry the duration")
+	}
+}
+
+func TestLogger_SkipsContextArguments(t *testing.T) {
+	for name, render := range map[string]Renderer{"Values": Values, "Truncated": Truncated(10)} {
+		t.Run(name, func(t *testing.T) {
+			records := setup(t, Config{Args: render}, "Fetch")
+			fetch := aspect.Wrap2("Fetch", func(ctx context.Context, id string) {})
+			fetch(context.Background(), "order-1")
+
+			args := attrs(records.records[0])["args"].Group()
+			if len(args) != 1 || args[0].Key != "1" || args[0].Value.String() != "order-1" {
+				t.Fatalf("expected only the id argument at its position, got %v", args)
+			}
+		})
+	}
+}
+
+func TestLogger_ErrorAndPanic(t *testing.T) {
+	errDenied := errors.New("denied")
+	records := setup(t, Config{Args: Omit}, "Save", "Crash")
+	save := aspect.Wrap1E("Save", func(secret string) error { return errDenied })
+	crash := aspect.Wrap0("Crash", func() { panic("boom") })
+
+	if err := save("password"); !errors.Is(err, errDenied) {
+		t.Fatalf("expected error to pass through, got %v", err)
+	}
+	failure := records.records[1]
+	if failure.Message != MessageError || failure.Level != slog.LevelError {
+		t.Fatalf("unexpected failure record %q at %v", failure.Message, failure.Level)
+	}
+	if failureAttrs := attrs(failure); failureAttrs["error"].Any() != errDenied {
+		t.Errorf("expected error attribute, got %v", failureAttrs["error"])
+	} else if _, logged := failureAttrs["args"]; logged {
+		t.Error("expected omitted arguments to stay out of the record")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected the panic to propagate")
+			}
+		}()
+		crash()
+	}()
+	if panicked := records.records[len(records.records)-1]; panicked.Message != MessagePanic || attrs(panicked)["panic"].Any() != "boom" {
+		t.Errorf("expected panic record, got %q", panicked.Message)
+	}
+}
+
+func TestLogger_SamplingKeepsFailures(t *testing.T) {
+	fail := false
+	records := setup(t, Config{Sample: Every(3), Levels: &Levels{Error: slog.LevelWarn}}, "Maybe")
+	maybe := aspect.Wrap0RE("Maybe", func() (bool, error) {
+		if fail {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	})
+
+	for range 6 {
+		_, _ = maybe()
+	}
+	if len(records.records) != 4 {
+		t.Fatalf("expected 2 sampled calls of 6 (4 records), got %d records", len(records.records))
+	}
+
+	fail = true
+	_, _ = maybe()
+	if last := records.records[len(records.records)-1]; last.Message != MessageError || last.Level != slog.LevelWarn {
+		t.Fatalf("expected unsampled failure at the configured level, got %q at %v", last.Message, last.Level)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	rendered := Truncated(4)([]any{"abcdefgh", 12})
+	if group := rendered.Group(); group[0].Value.String() != "abcd..." || group[1].Value.String() != "12" {
+		t.Fatalf("unexpected truncated rendering %v", rendered)
+	}
+}
+
+func TestLogger_RedactsByDefault(t *testing.T) {
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	records := &recorder{}
+	registry.MustRegister("Login", aspect.WithRedactedArgs(1))
+	registry.MustApply(aspect.On("Login"), New(Config{Logger: slog.New(records)}))
+	login := aspect.Wrap2E("Login", func(user, password string) error { return nil })
+
+	_ = login("alice", "hunter2")
+	if args := attrs(records.records[0])["args"].Group(); args[0].Value.String() != "alice" || args[1].Value.String() != aspect.RedactedMask {
+		t.Fatalf("expected redacted password in the entry record, got %v", args)
+	}
+}
@@ -0,0 +1,52 @@
+// Package logging - render defines how arguments and results appear in log records
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Renderer turns the arguments or results of a call into a log attribute value.
+// Returning an empty slog.Value omits the attribute.
+type Renderer func(values []any) slog.Value
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Values renders every value as is, in a group keyed by position ("0", "1", ...). A context.Context
+// is left out, its position skipped.
+func Values(values []any) slog.Value {
+	attrs := make([]slog.Attr, 0, len(values))
+	for index, value := range values {
+		if _, isContext := value.(context.Context); !isContext {
+			attrs = append(attrs, slog.Any(strconv.Itoa(index), value))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Omit leaves the values out of the record, e.g. for functions handling secrets.
+func Omit(values []any) slog.Value {
+	return slog.Value{}
+}
+
+// Truncated renders values like Values, formatted with %v and cut to maxLength bytes.
+func Truncated(maxLength int) Renderer {
+	return func(values []any) slog.Value {
+		attrs := make([]slog.Attr, 0, len(values))
+		for index, value := range values {
+			if _, isContext := value.(context.Context); isContext {
+				continue
+			}
+			text := fmt.Sprintf("%v", value)
+			if maxLength >= 0 && len(text) > maxLength {
+				text = text[:maxLength] + "..."
+			}
+			attrs = append(attrs, slog.String(strconv.Itoa(index), text))
+		}
+		return slog.GroupValue(attrs...)
+	}
+}
@@ -0,0 +1,152 @@
+// Package metrics - exposition renders metrics and registry gauges in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// labelEscaper escapes label values as required by the text format.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Handler returns an http.Handler serving the metrics in the Prometheus text format, e.g. on /metrics.
+func (metrics *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Header().Set("Content-Type", ContentType)
+		_ = metrics.Write(writer)
+	})
+}
+
+// Write writes the metrics and the gauges of the attached registries in the Prometheus text format.
+func (metrics *Metrics) Write(writer io.Writer) error {
+	buffered := bufio.NewWriter(writer)
+	namespace := metrics.config.Namespace
+	snapshot := metrics.Snapshot()
+
+	counters := []struct {
+		name, help string
+		value      func(series Series) uint64
+	}{
+		{"calls_total", "Completed calls per function.", func(series Series) uint64 { return series.Calls }},
+		{"errors_total", "Calls per function that returned an error.", func(series Series) uint64 { return series.Errors }},
+		{"panics_total", "Calls per function that panicked.", func(series Series) uint64 { return series.Panics }},
+		{"degraded_total", "Calls per function answered with fallback results.", func(series Series) uint64 { return series.Degraded }},
+	}
+	for _, counter := range counters {
+		name := namespace + "_" + counter.name
+		writeHeader(buffered, name, counter.help, "counter")
+		for _, series := range snapshot {
+			fmt.Fprintf(buffered, "%s%s %d\n", name, metrics.labels(series, ""), counter.value(series))
+		}
+	}
+
+	name := namespace + "_call_duration_seconds"
+	writeHeader(buffered, name, "Call latency per function in seconds.", "histogram")
+	for _, series := range snapshot {
+		for _, bucket := range series.Duration.Buckets {
+			fmt.Fprintf(buffered, "%s_bucket%s %d\n", name, metrics.labels(series, formatFloat(bucket.UpperBound)), bucket.Count)
+		}
+		fmt.Fprintf(buffered, "%s_bucket%s %d\n", name, metrics.labels(series, "+Inf"), series.Duration.Count)
+		fmt.Fprintf(buffered, "%s_sum%s %s\n", name, metrics.labels(series, ""), formatFloat(series.Duration.Sum))
+		fmt.Fprintf(buffered, "%s_count%s %d\n", name, metrics.labels(series, ""), series.Duration.Count)
+	}
+
+	metrics.writeGauges(buffered)
+	return buffered.Flush()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// writeGauges writes the gauges of the attached registries, one metric per gauge name keyed by a "key" label.
+func (metrics *Metrics) writeGauges(writer io.Writer) {
+	metrics.mu.RLock()
+	registries := append([]*aspect.Registry(nil), metrics.registries...)
+	metrics.mu.RUnlock()
+
+	values := make(map[string]map[string]int64)
+	for _, registry := range registries {
+		for _, gauge := range registry.Gauges() {
+			name := metrics.config.Namespace + "_" + sanitize(gauge.Name)
+			if values[name] == nil {
+				values[name] = make(map[string]int64)
+			}
+			values[name][gauge.Key] += gauge.Value()
+		}
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		writeHeader(writer, name, "Registry gauge.", "gauge")
+		keys := make([]string, 0, len(values[name]))
+		for key := range values[name] {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if key == "" {
+				fmt.Fprintf(writer, "%s %d\n", name, values[name][key])
+				continue
+			}
+			fmt.Fprintf(writer, "%s{key=\"%s\"} %d\n", name, labelEscaper.Replace(key), values[name][key])
+		}
+	}
+}
+
+// labels renders the label set of a series, with an "le" label for histogram buckets.
+func (metrics *Metrics) labels(series Series, le string) string {
+	var builder strings.Builder
+	builder.WriteString(`{function="`)
+	builder.WriteString(labelEscaper.Replace(series.Function))
+	builder.WriteString(`"`)
+	for _, label := range metrics.config.Labels {
+		fmt.Fprintf(&builder, `,%s="%s"`, label.Name, labelEscaper.Replace(series.Labels[label.Name]))
+	}
+	if le != "" {
+		fmt.Fprintf(&builder, `,le="%s"`, le)
+	}
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric.
+func writeHeader(writer io.Writer, name, help, metricType string) {
+	fmt.Fprintf(writer, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+}
+
+// formatFloat renders a float as the text format expects.
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// sanitize turns a gauge name such as "bulkhead.active" into a metric name such as "bulkhead_active".
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
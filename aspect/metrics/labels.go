@@ -0,0 +1,50 @@
+// Package metrics - labels derives extra series labels from function tags and call arguments
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Label adds a label to the series of a call.
+type Label struct {
+	Name  string                           // Name is the Prometheus label name, e.g. "tenant".
+	Value func(ctx *aspect.Context) string // Value derives the label value of a call.
+
+	tagKey   string // tagKey is the tag read by a Tag label, whose values come from the cached function tags.
+	isTag    bool   // isTag marks labels created by Tag.
+	argIndex int    // argIndex is the argument read by an Arg label.
+	isArg    bool   // isArg marks labels created by Arg, whose redaction comes from the cached registration.
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Tag labels calls with a tag given to their function at registration (aspect.WithTags).
+// Tags are read from the registries the metrics are applied to, once per function.
+func Tag(name, key string) Label {
+	return Label{Name: name, tagKey: key, isTag: true}
+}
+
+// Arg labels calls with the argument at index formatted with %v, redacted as in aspect.Redacted;
+// the redacted positions are read from the registries the metrics are applied to, once per function.
+// Only use it for arguments with few distinct values; MaxSeries bounds the damage otherwise.
+func Arg(name string, index int) Label {
+	return Label{Name: name, argIndex: index, isArg: true}
+}
+
+// Metadata labels calls with a context metadata value set by higher-priority advice, formatted with %v.
+func Metadata(name, key string) Label {
+	return Label{
+		Name: name,
+		Value: func(ctx *aspect.Context) string {
+			value, ok := ctx.Metadata[key]
+			if !ok {
+				return ""
+			}
+			return fmt.Sprintf("%v", value)
+		},
+	}
+}
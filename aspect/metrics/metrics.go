@@ -0,0 +1,410 @@
+// Package metrics - metrics provides an aspect counting calls, errors, panics and degraded responses and recording
+// latency histograms per function, without external dependencies
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// OverflowValue replaces every label value of calls beyond a function's MaxSeries label combinations.
+const OverflowValue = "other"
+
+const (
+	defaultName      = "metrics"
+	defaultNamespace = "aspect"
+	defaultMaxSeries = 100
+)
+
+// DefaultBuckets are the latency histogram upper bounds in seconds, as in Prometheus client libraries.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelName matches valid Prometheus label and metric names.
+var labelName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Config configures a Metrics aspect. Zero values fall back to defaults.
+type Config struct {
+	Name      string    // Name of the advice, for runtime switches (default "metrics").
+	Priority  int       // Priority of the Around advice; keep it highest to time the whole chain.
+	Namespace string    // Namespace prefixes the exposed metric names (default "aspect").
+	Buckets   []float64 // Buckets are the increasing latency histogram upper bounds in seconds (default DefaultBuckets).
+	Labels    []Label   // Labels are added to the "function" label, e.g. Tag("team", "team") or Arg("region", 1).
+
+	// MaxSeries bounds the label combinations of a function (default 100); further combinations
+	// are recorded in a single series whose extra labels are all OverflowValue.
+	MaxSeries int
+}
+
+// Series is a snapshot of the counters of a function and label combination.
+type Series struct {
+	Function string            // Function is the registered function name.
+	Labels   map[string]string // Labels are the values of Config.Labels (empty without extra labels).
+	Calls    uint64            // Calls are the completed invocations, including failed and panicking ones.
+	Errors   uint64            // Errors are invocations that returned an error, including rejections by other advice.
+	Panics   uint64            // Panics are invocations that panicked.
+	Degraded uint64            // Degraded are invocations answered with fallback results (aspect.Context.Degraded).
+	Duration Histogram         // Duration is the latency distribution of the invocations.
+}
+
+// Histogram is a snapshot of a latency distribution.
+type Histogram struct {
+	Buckets []Bucket // Buckets hold cumulative counts per upper bound, without the implicit +Inf bucket.
+	Sum     float64  // Sum is the total latency in seconds.
+	Count   uint64   // Count is the number of observations.
+}
+
+// Bucket is a cumulative histogram bucket.
+type Bucket struct {
+	UpperBound float64 // UpperBound is the inclusive upper bound in seconds.
+	Count      uint64  // Count is the number of observations less than or equal to UpperBound.
+}
+
+// Metrics is an aspect recording per-function call metrics, exposed by Snapshot and Handler.
+// Gauges of the registries it is applied to (e.g. bulkhead queues) are exposed alongside.
+type Metrics struct {
+	config Config
+
+	mu            sync.RWMutex
+	functions     map[string]*function
+	registries    []*aspect.Registry
+	registrations map[string]registration // registrations caches what Tag and Arg labels read from registries.
+	unsubscribes  []func()
+}
+
+// registration is the cached registration of a function, fixed until it is registered again.
+type registration struct {
+	tags         map[string]string // tags are read by Tag labels.
+	redactedArgs []int             // redactedArgs are the masked positions for Arg labels.
+}
+
+// function holds the series of a single function.
+type function struct {
+	series   map[string]*series
+	overflow *series
+}
+
+// series holds the live counters of a label combination.
+type series struct {
+	function string
+	values   []string
+
+	mu                    sync.Mutex
+	calls, errors, panics uint64
+	degraded              uint64
+	buckets               []uint64 // buckets are non-cumulative counts per Config.Buckets bound.
+	sum                   float64
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Metrics aspect, filling unset configuration with defaults.
+func New(config Config) *Metrics {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+	if config.Buckets == nil {
+		config.Buckets = DefaultBuckets
+	}
+	if config.MaxSeries <= 0 {
+		config.MaxSeries = defaultMaxSeries
+	}
+	return &Metrics{config: config, functions: make(map[string]*function), registrations: make(map[string]registration)}
+}
+
+// Name returns the advice name.
+func (metrics *Metrics) Name() string {
+	return metrics.config.Name
+}
+
+// Advice returns the Around advice recording the call.
+func (metrics *Metrics) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     metrics.config.Name,
+			Type:     aspect.Around,
+			Priority: metrics.config.Priority,
+			Handler:  metrics.around,
+		},
+	}
+}
+
+// Attach implements aspect.RegistryAware; the gauges of every attached registry are exposed
+// and Tag and Arg labels read the registrations of its functions.
+func (metrics *Metrics) Attach(registry *aspect.Registry) {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	metrics.registries = append(metrics.registries, registry)
+	metrics.unsubscribes = append(metrics.unsubscribes, registry.Subscribe(metrics.forget))
+}
+
+// Init validates the configuration.
+func (metrics *Metrics) Init() error {
+	if !labelName.MatchString(metrics.config.Namespace) {
+		return fmt.Errorf("metrics '%s': invalid namespace '%s'", metrics.config.Name, metrics.config.Namespace)
+	}
+	for index := 1; index < len(metrics.config.Buckets); index++ {
+		if metrics.config.Buckets[index] <= metrics.config.Buckets[index-1] {
+			return fmt.Errorf("metrics '%s': buckets must be increasing", metrics.config.Name)
+		}
+	}
+	seen := map[string]bool{"function": true, "le": true}
+	for _, label := range metrics.config.Labels {
+		if !labelName.MatchString(label.Name) || seen[label.Name] || (label.Value == nil && !label.isTag && !label.isArg) {
+			return fmt.Errorf("metrics '%s': invalid or duplicate label '%s'", metrics.config.Name, label.Name)
+		}
+		seen[label.Name] = true
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; it stops following registry events, recorded metrics stay readable.
+func (metrics *Metrics) Close() error {
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	for _, unsubscribe := range metrics.unsubscribes {
+		unsubscribe()
+	}
+	metrics.unsubscribes = nil
+	return nil
+}
+
+// Snapshot returns the series of every function sorted by function name and label values.
+func (metrics *Metrics) Snapshot() []Series {
+	metrics.mu.RLock()
+	var all []*series
+	for _, function := range metrics.functions {
+		for _, series := range function.series {
+			all = append(all, series)
+		}
+		if function.overflow != nil {
+			all = append(all, function.overflow)
+		}
+	}
+	metrics.mu.RUnlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].function != all[j].function {
+			return all[i].function < all[j].function
+		}
+		return slices.Compare(all[i].values, all[j].values) < 0
+	})
+
+	snapshot := make([]Series, len(all))
+	for index, series := range all {
+		snapshot[index] = metrics.snapshot(series)
+	}
+	return snapshot
+}
+
+// Function returns the series of a single function, see Snapshot.
+func (metrics *Metrics) Function(functionName string) []Series {
+	var selected []Series
+	for _, series := range metrics.Snapshot() {
+		if series.Function == functionName {
+			selected = append(selected, series)
+		}
+	}
+	return selected
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around times the call and records its outcome, including panics.
+func (metrics *Metrics) around(ctx *aspect.Context) error {
+	series := metrics.series(ctx)
+	start := time.Now()
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			series.observe(time.Since(start), metrics.config.Buckets, false, true, false)
+			panic(panicValue)
+		}
+	}()
+
+	_ = ctx.Proceed()
+	series.observe(time.Since(start), metrics.config.Buckets, ctx.Error != nil, false, ctx.Degraded)
+	return nil
+}
+
+// registration returns the registration of a function from the first attached registry that has
+// it, caching it since tags and redaction rules are fixed at registration.
+func (metrics *Metrics) registration(functionName string) (registration, bool) {
+	metrics.mu.RLock()
+	cached, exists := metrics.registrations[functionName]
+	registries := metrics.registries
+	metrics.mu.RUnlock()
+	if exists {
+		return cached, true
+	}
+
+	for _, registry := range registries {
+		if registry.IsRegistered(functionName) {
+			resolved := registration{tags: registry.Tags(functionName), redactedArgs: registry.RedactedArgs(functionName)}
+			metrics.mu.Lock()
+			metrics.registrations[functionName] = resolved
+			metrics.mu.Unlock()
+			return resolved, true
+		}
+	}
+	return registration{}, false
+}
+
+// forget drops cached registrations when a function may have been registered again with other options.
+func (metrics *Metrics) forget(event aspect.Event) {
+	if event.Type != aspect.FunctionUnregistered && event.Type != aspect.RegistryCleared {
+		return
+	}
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	if event.Type == aspect.RegistryCleared {
+		clear(metrics.registrations)
+		return
+	}
+	delete(metrics.registrations, event.FunctionName)
+}
+
+// argValue formats the argument of an Arg label, masking it if its position is redacted; without a
+// known registration it falls back to the aspect.Redacted view of the call.
+func argValue(ctx *aspect.Context, index int, registration registration, registered bool) string {
+	if index < 0 || index >= len(ctx.Args) {
+		return ""
+	}
+	switch {
+	case !registered:
+		return fmt.Sprintf("%v", aspect.Redacted(ctx).Args[index])
+	case slices.Contains(registration.redactedArgs, index):
+		return aspect.RedactedMask
+	}
+	return fmt.Sprintf("%v", aspect.RedactValue(ctx.Args[index]))
+}
+
+// series returns the series of a call, creating it within the function's MaxSeries.
+func (metrics *Metrics) series(ctx *aspect.Context) *series {
+	values := make([]string, len(metrics.config.Labels))
+	var resolved *registration
+	registered := false
+	for index, label := range metrics.config.Labels {
+		if (label.isTag || label.isArg) && resolved == nil {
+			var found registration
+			found, registered = metrics.registration(ctx.FunctionName)
+			resolved = &found
+		}
+		switch {
+		case label.isTag:
+			values[index] = resolved.tags[label.tagKey]
+		case label.isArg:
+			values[index] = argValue(ctx, label.argIndex, *resolved, registered)
+		default:
+			values[index] = label.Value(ctx)
+		}
+	}
+	key := strings.Join(values, "\xff")
+
+	metrics.mu.RLock()
+	if existing, exists := metrics.functions[ctx.FunctionName]; exists {
+		if found, exists := existing.series[key]; exists {
+			metrics.mu.RUnlock()
+			return found
+		}
+	}
+	metrics.mu.RUnlock()
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+
+	fn, exists := metrics.functions[ctx.FunctionName]
+	if !exists {
+		fn = &function{series: make(map[string]*series)}
+		metrics.functions[ctx.FunctionName] = fn
+	}
+	if found, exists := fn.series[key]; exists {
+		return found
+	}
+
+	if len(fn.series) >= metrics.config.MaxSeries {
+		if fn.overflow == nil {
+			overflowValues := make([]string, len(values))
+			for index := range overflowValues {
+				overflowValues[index] = OverflowValue
+			}
+			fn.overflow = metrics.newSeries(ctx.FunctionName, overflowValues)
+		}
+		return fn.overflow
+	}
+
+	created := metrics.newSeries(ctx.FunctionName, values)
+	fn.series[key] = created
+	return created
+}
+
+// newSeries creates an empty series.
+func (metrics *Metrics) newSeries(functionName string, values []string) *series {
+	return &series{function: functionName, values: values, buckets: make([]uint64, len(metrics.config.Buckets))}
+}
+
+// snapshot copies the counters of a series.
+func (metrics *Metrics) snapshot(series *series) Series {
+	labels := make(map[string]string, len(series.values))
+	for index, value := range series.values {
+		labels[metrics.config.Labels[index].Name] = value
+	}
+
+	series.mu.Lock()
+	defer series.mu.Unlock()
+
+	buckets := make([]Bucket, len(series.buckets))
+	var cumulative uint64
+	for index, count := range series.buckets {
+		cumulative += count
+		buckets[index] = Bucket{UpperBound: metrics.config.Buckets[index], Count: cumulative}
+	}
+	return Series{
+		Function: series.function,
+		Labels:   labels,
+		Calls:    series.calls,
+		Errors:   series.errors,
+		Panics:   series.panics,
+		Degraded: series.degraded,
+		Duration: Histogram{Buckets: buckets, Sum: series.sum, Count: series.calls},
+	}
+}
+
+// observe records the outcome of a call.
+func (series *series) observe(elapsed time.Duration, bounds []float64, failed, panicked, degraded bool) {
+	seconds := elapsed.Seconds()
+	bucket, _ := slices.BinarySearch(bounds, seconds)
+
+	series.mu.Lock()
+	defer series.mu.Unlock()
+
+	series.calls++
+	if failed {
+		series.errors++
+	}
+	if panicked {
+		series.panics++
+	}
+	if degraded {
+		series.degraded++
+	}
+	if bucket < len(series.buckets) {
+		series.buckets[bucket]++
+	}
+	series.sum += seconds
+}
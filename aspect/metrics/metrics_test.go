@@ -0,0 +1,215 @@
+// Package metrics - metrics_test validates recorded series, cardinality limits and the text exposition
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/fallback"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies the metrics aspect.
+func setup(t *testing.T, metrics *Metrics, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name, aspect.WithTags(map[string]string{"team": "payments"}))
+	}
+	registry.MustApply(aspect.On(names...), metrics)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestMetrics_CountsAndLatency(t *testing.T) {
+	metrics := New(Config{Buckets: []float64{0.001, 10}})
+	setup(t, metrics, "Charge")
+
+	fail := false
+	charge := aspect.Wrap1E("Charge", func(amount int) error {
+		if amount < 0 {
+			panic("negative amount")
+		}
+		if fail {
+			return errors.New("declined")
+		}
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	})
+
+	_ = charge(10)
+	fail = true
+	_ = charge(20)
+	func() {
+		defer func() { _ = recover() }()
+		_ = charge(-1)
+	}()
+
+	series := metrics.Function("Charge")
+	if len(series) != 1 {
+		t.Fatalf("expected a single series without labels, got %d", len(series))
+	}
+	if got := series[0]; got.Calls != 3 || got.Errors != 1 || got.Panics != 1 {
+		t.Fatalf("expected 3 calls, 1 error and 1 panic, got %+v", got)
+	}
+
+	histogram := series[0].Duration
+	if histogram.Count != 3 || histogram.Buckets[1].Count != 3 || histogram.Buckets[0].Count > 2 {
+		t.Errorf("expected the slow call above the first bucket, got %+v", histogram)
+	}
+	if histogram.Sum < 0.002 {
+		t.Errorf("expected the latency sum to include the slow call, got %v", histogram.Sum)
+	}
+}
+
+func TestMetrics_LabelsAndCardinalityLimit(t *testing.T) {
+	metrics := New(Config{
+		Labels:    []Label{Tag("team", "team"), Arg("region", 0)},
+		MaxSeries: 2,
+	})
+	setup(t, metrics, "Lookup")
+	lookup := aspect.Wrap1("Lookup", func(region string) {})
+
+	for _, region := range []string{"eu", "us", "eu", "ap", "sa"} {
+		lookup(region)
+	}
+
+	series := metrics.Function("Lookup")
+	if len(series) != 3 {
+		t.Fatalf("expected 2 series and the overflow series, got %d", len(series))
+	}
+	calls := make(map[string]uint64)
+	for _, got := range series {
+		calls[got.Labels["team"]+"/"+got.Labels["region"]] = got.Calls
+	}
+	if calls["payments/eu"] != 2 || calls["payments/us"] != 1 {
+		t.Errorf("expected labelled series for the first 2 combinations, got %v", calls)
+	}
+	if calls[OverflowValue+"/"+OverflowValue] != 2 {
+		t.Errorf("expected later combinations in the overflow series, got %v", calls)
+	}
+}
+
+func TestMetrics_TagsFollowReregistration(t *testing.T) {
+	metrics := New(Config{Labels: []Label{Tag("team", "team")}})
+	registry := setup(t, metrics, "Refund")
+	refund := aspect.Wrap0("Refund", func() {})
+	refund()
+
+	registry.MustUnregister("Refund")
+	registry.MustRegister("Refund", aspect.WithTags(map[string]string{"team": "billing"}))
+	registry.MustApply(aspect.On("Refund"), metrics)
+	refund()
+
+	teams := map[string]uint64{}
+	for _, series := range metrics.Function("Refund") {
+		teams[series.Labels["team"]] = series.Calls
+	}
+	if teams["payments"] != 1 || teams["billing"] != 1 {
+		t.Fatalf("expected one call per registration's team tag, got %v", teams)
+	}
+}
+
+func TestMetrics_ArgLabelsFollowRedaction(t *testing.T) {
+	metrics := New(Config{Labels: []Label{Arg("plan", 0), Arg("coupon", 1)}})
+	registry := setup(t, metrics, "Subscribe")
+	registry.MustUnregister("Subscribe")
+	registry.MustRegister("Subscribe", aspect.WithRedactedArgs(1))
+	registry.MustApply(aspect.On("Subscribe"), metrics)
+	subscribe := aspect.Wrap2("Subscribe", func(plan, coupon string) {})
+	subscribe("pro", "FREE-MONTH")
+
+	registry.MustUnregister("Subscribe")
+	registry.MustRegister("Subscribe")
+	registry.MustApply(aspect.On("Subscribe"), metrics)
+	subscribe("pro", "WELCOME")
+
+	coupons := map[string]bool{}
+	for _, series := range metrics.Function("Subscribe") {
+		if series.Labels["plan"] != "pro" {
+			t.Errorf("expected the plan argument as is, got %v", series.Labels)
+		}
+		coupons[series.Labels["coupon"]] = true
+	}
+	if !coupons[aspect.RedactedMask] || !coupons["WELCOME"] || coupons["FREE-MONTH"] {
+		t.Fatalf("expected the coupon masked only while registered as redacted, got %v", coupons)
+	}
+}
+
+func TestMetrics_CountsDegradedResponses(t *testing.T) {
+	metrics := New(Config{Priority: 100})
+	registry := setup(t, metrics, "Quote")
+	registry.MustApply(aspect.On("Quote"), fallback.New(fallback.Config{Chain: []fallback.Func{fallback.Value(0)}}))
+
+	quote := aspect.Wrap1RE("Quote", func(sku string) (int, error) {
+		if sku == "" {
+			return 0, errors.New("pricing unavailable")
+		}
+		return 42, nil
+	})
+	_, _ = quote("sku-1")
+	if result, err := quote(""); err != nil || result != 0 {
+		t.Fatalf("expected the fallback result, got (%d, %v)", result, err)
+	}
+
+	if series := metrics.Function("Quote"); series[0].Calls != 2 || series[0].Degraded != 1 || series[0].Errors != 0 {
+		t.Fatalf("expected 1 degraded call out of 2 without errors, got %+v", series[0])
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	metrics := New(Config{Namespace: "shop", Buckets: []float64{1}, Labels: []Label{Tag("team", "team")}})
+	registry := setup(t, metrics, "Checkout")
+	_ = registry.RegisterGauge(aspect.Gauge{Name: "bulkhead.active", Key: "Checkout", Value: func() int64 { return 4 }})
+
+	checkout := aspect.Wrap0("Checkout", func() {})
+	checkout()
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != ContentType {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+	body := recorder.Body.String()
+	for _, line := range []string{
+		"# TYPE shop_calls_total counter",
+		`shop_calls_total{function="Checkout",team="payments"} 1`,
+		`shop_errors_total{function="Checkout",team="payments"} 0`,
+		`shop_degraded_total{function="Checkout",team="payments"} 0`,
+		"# TYPE shop_call_duration_seconds histogram",
+		`shop_call_duration_seconds_bucket{function="Checkout",team="payments",le="1"} 1`,
+		`shop_call_duration_seconds_bucket{function="Checkout",team="payments",le="+Inf"} 1`,
+		`shop_call_duration_seconds_count{function="Checkout",team="payments"} 1`,
+		"# TYPE shop_bulkhead_active gauge",
+		`shop_bulkhead_active{key="Checkout"} 4`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected exposition to contain %q, got:\n%s", line, body)
+		}
+	}
+}
+
+func TestMetrics_InitValidation(t *testing.T) {
+	invalid := []Config{
+		{Buckets: []float64{1, 0.5}},
+		{Labels: []Label{Arg("function", 0)}},
+		{Labels: []Label{Arg("bad-name", 0)}},
+		{Namespace: "1st"},
+	}
+	for _, config := range invalid {
+		if err := New(config).Init(); err == nil {
+			t.Errorf("expected error for %+v", config)
+		}
+	}
+}
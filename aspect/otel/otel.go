@@ -0,0 +1,200 @@
+// Package otel - otel provides an aspect turning wrapped calls into OpenTelemetry spans and metrics.
+// It is a separate module so the core library stays free of dependencies.
+package otel
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// InstrumentationName is the default tracer and meter name.
+const InstrumentationName = "github.com/seyedali-dev/gosaidsno/aspect/otel"
+
+// Outcomes recorded in the "aspect.outcome" metric attribute.
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+	OutcomePanic = "panic"
+)
+
+const defaultName = "otel"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Config configures a Bridge. Zero values fall back to defaults.
+type Config struct {
+	Name            string               // Name of the advice, for runtime switches (default "otel").
+	Priority        int                  // Priority of the Around advice; keep it highest to time the whole chain.
+	TracerProvider  trace.TracerProvider // TracerProvider creates the spans (default: the global provider).
+	MeterProvider   metric.MeterProvider // MeterProvider records the metrics (default: the global provider).
+	Instrumentation string               // Instrumentation names the tracer and meter (default InstrumentationName).
+	OmitArgs        bool                 // OmitArgs leaves the "aspect.arg.<index>" attributes out of the spans.
+
+	// Attributes adds span and metric attributes derived from a call (optional). Keep their
+	// cardinality low, as they label the metrics too.
+	Attributes func(ctx *aspect.Context) []attribute.KeyValue
+}
+
+// Bridge is an aspect recording an OpenTelemetry span, an "aspect.calls" counter and an
+// "aspect.call.duration" histogram per invocation. Functions taking a context.Context as first
+// argument receive one carrying their span, so nested wrapped calls become child spans.
+type Bridge struct {
+	config Config
+
+	tracer   trace.Tracer
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Bridge, filling unset configuration with defaults.
+func New(config Config) *Bridge {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = otelapi.GetTracerProvider()
+	}
+	if config.MeterProvider == nil {
+		config.MeterProvider = otelapi.GetMeterProvider()
+	}
+	if config.Instrumentation == "" {
+		config.Instrumentation = InstrumentationName
+	}
+	return &Bridge{config: config}
+}
+
+// Name returns the advice name.
+func (bridge *Bridge) Name() string {
+	return bridge.config.Name
+}
+
+// Advice returns the Around advice recording the call.
+func (bridge *Bridge) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     bridge.config.Name,
+			Type:     aspect.Around,
+			Priority: bridge.config.Priority,
+			Handler:  bridge.around,
+		},
+	}
+}
+
+// Init creates the tracer and the metric instruments.
+func (bridge *Bridge) Init() error {
+	meter := bridge.config.MeterProvider.Meter(bridge.config.Instrumentation)
+	calls, err := meter.Int64Counter("aspect.calls",
+		metric.WithDescription("Completed calls of wrapped functions."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return fmt.Errorf("otel '%s': %w", bridge.config.Name, err)
+	}
+	duration, err := meter.Float64Histogram("aspect.call.duration",
+		metric.WithDescription("Duration of wrapped function calls."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return fmt.Errorf("otel '%s': %w", bridge.config.Name, err)
+	}
+
+	bridge.tracer = bridge.config.TracerProvider.Tracer(bridge.config.Instrumentation)
+	bridge.calls, bridge.duration = calls, duration
+	return nil
+}
+
+// Close implements aspect.Aspect; the providers are owned by the caller.
+func (bridge *Bridge) Close() error {
+	return nil
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around starts a span under the caller's span, passes it on through the context argument,
+// and records the outcome once the call returns or panics.
+func (bridge *Bridge) around(ctx *aspect.Context) error {
+	attributes := []attribute.KeyValue{attribute.String("code.function.name", ctx.FunctionName)}
+	if bridge.config.Attributes != nil {
+		attributes = append(attributes, bridge.config.Attributes(ctx)...)
+	}
+
+	parent := ctx.Context()
+	spanCtx, span := bridge.tracer.Start(parent, ctx.FunctionName,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attributes...),
+		trace.WithAttributes(bridge.args(ctx)...))
+
+	if len(ctx.Args) > 0 {
+		if _, acceptsContext := ctx.Args[0].(context.Context); acceptsContext {
+			ctx.Args[0] = spanCtx
+			defer func() { ctx.Args[0] = parent }()
+		}
+	}
+
+	start := time.Now()
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			message := fmt.Sprintf("panic: %v", panicValue)
+			span.AddEvent("panic", trace.WithAttributes(
+				attribute.String("exception.message", message),
+				attribute.String("exception.stacktrace", string(debug.Stack()))))
+			span.SetStatus(codes.Error, message)
+			bridge.record(spanCtx, span, start, OutcomePanic, attributes)
+			panic(panicValue)
+		}
+	}()
+
+	_ = ctx.Proceed()
+
+	outcome := OutcomeOK
+	if ctx.Error != nil {
+		outcome = OutcomeError
+		span.RecordError(ctx.Error)
+		span.SetStatus(codes.Error, ctx.Error.Error())
+	}
+	if ctx.Skipped {
+		span.SetAttributes(attribute.Bool("aspect.skipped", true))
+	}
+	if ctx.Degraded {
+		span.SetAttributes(attribute.Bool("aspect.degraded", true))
+	}
+	bridge.record(spanCtx, span, start, outcome, attributes)
+	return nil
+}
+
+// record ends the span and records the call metrics.
+func (bridge *Bridge) record(spanCtx context.Context, span trace.Span, start time.Time, outcome string, attributes []attribute.KeyValue) {
+	span.End()
+
+	options := metric.WithAttributes(append(attributes, attribute.String("aspect.outcome", outcome))...)
+	bridge.calls.Add(spanCtx, 1, options)
+	bridge.duration.Record(spanCtx, time.Since(start).Seconds(), options)
+}
+
+// args returns the span attributes of the redacted arguments, leaving out context.Context values.
+func (bridge *Bridge) args(ctx *aspect.Context) []attribute.KeyValue {
+	if bridge.config.OmitArgs {
+		return nil
+	}
+
+	var attributes []attribute.KeyValue
+	for index, arg := range aspect.Redacted(ctx).Args {
+		if _, isContext := arg.(context.Context); isContext {
+			continue
+		}
+		attributes = append(attributes, attribute.String("aspect.arg."+strconv.Itoa(index), fmt.Sprintf("%v", arg)))
+	}
+	return attributes
+}
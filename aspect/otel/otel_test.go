@@ -0,0 +1,131 @@
+// Package otel - otel_test validates spans and metrics with the in-memory SDK exporters
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies a Bridge recording into
+// an in-memory span recorder and a manual metric reader.
+func setup(t *testing.T, names ...string) (*tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	for _, name := range names {
+		registry.MustRegister(name, aspect.WithRedactedArgs(1))
+	}
+	registry.MustApply(aspect.On(names...), New(Config{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)),
+		MeterProvider:  sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}))
+	return spans, reader
+}
+
+// attributeValue returns the value of a span attribute.
+func attributeValue(attributes []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, kv := range attributes {
+		if string(kv.Key) == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestBridge_NestedSpansAndStatus(t *testing.T) {
+	spans, _ := setup(t, "Handle", "Query")
+	query := aspect.Wrap2RE("Query", func(ctx context.Context, password string) (int, error) {
+		return 0, errors.New("no rows")
+	})
+	handle := aspect.Wrap1E("Handle", func(ctx context.Context) error {
+		_, _ = query(ctx, "secret")
+		return nil
+	})
+
+	if err := handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	child, root := ended[0], ended[1]
+	if child.Name() != "Query" || root.Name() != "Handle" {
+		t.Fatalf("unexpected spans %q and %q", child.Name(), root.Name())
+	}
+	if child.Parent().SpanID() != root.SpanContext().SpanID() || child.SpanContext().TraceID() != root.SpanContext().TraceID() {
+		t.Error("expected Query to be a child of Handle")
+	}
+	if child.Status().Code != codes.Error || child.Status().Description != "no rows" || len(child.Events()) != 1 {
+		t.Errorf("expected error status and event, got %+v", child.Status())
+	}
+	if root.Status().Code != codes.Unset {
+		t.Errorf("expected unset status on success, got %+v", root.Status())
+	}
+	if password, ok := attributeValue(child.Attributes(), "aspect.arg.1"); !ok || password.AsString() != aspect.RedactedMask {
+		t.Errorf("expected redacted argument attribute, got %v", child.Attributes())
+	}
+}
+
+func TestBridge_PanicAndMetrics(t *testing.T) {
+	spans, reader := setup(t, "Crash")
+	crash := aspect.Wrap1("Crash", func(fail bool) {
+		if fail {
+			panic("boom")
+		}
+	})
+
+	crash(false)
+	func() {
+		defer func() { _ = recover() }()
+		crash(true)
+	}()
+
+	if panicked := spans.Ended()[1]; panicked.Status().Code != codes.Error || panicked.Events()[0].Name != "panic" {
+		t.Errorf("expected panic status and event, got %+v", panicked.Status())
+	}
+
+	var collected metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &collected); err != nil {
+		t.Fatal(err)
+	}
+	outcomes := make(map[string]int64)
+	var histogramCount uint64
+	for _, scope := range collected.ScopeMetrics {
+		for _, data := range scope.Metrics {
+			switch data.Name {
+			case "aspect.calls":
+				for _, point := range data.Data.(metricdata.Sum[int64]).DataPoints {
+					outcome, _ := point.Attributes.Value("aspect.outcome")
+					outcomes[outcome.AsString()] += point.Value
+				}
+			case "aspect.call.duration":
+				for _, point := range data.Data.(metricdata.Histogram[float64]).DataPoints {
+					histogramCount += point.Count
+				}
+			}
+		}
+	}
+	if outcomes[OutcomeOK] != 1 || outcomes[OutcomePanic] != 1 || histogramCount != 2 {
+		t.Fatalf("expected 1 ok and 1 panic call with durations, got %v and %d", outcomes, histogramCount)
+	}
+}
@@ -0,0 +1,125 @@
+// Package aspect - pointcut selects registered functions by name, pattern or tag
+package aspect
+
+import (
+	"maps"
+	"path"
+	"slices"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// FunctionInfo describes a registered function to a Pointcut.
+type FunctionInfo struct {
+	Name string            // Name is the registered function name.
+	Tags map[string]string // Tags are the key-value tags given at registration.
+}
+
+// Pointcut decides whether a registered function is selected.
+type Pointcut func(fn FunctionInfo) bool
+
+// RegisterOption configures a function at registration time.
+type RegisterOption func(chain *AdviceChain)
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// WithTags attaches key-value tags to a function at registration, for pointcuts and observability.
+func WithTags(tags map[string]string) RegisterOption {
+	return func(chain *AdviceChain) {
+		if chain.tags == nil {
+			chain.tags = make(map[string]string, len(tags))
+		}
+		maps.Copy(chain.tags, tags)
+	}
+}
+
+// On selects functions by exact name.
+func On(names ...string) Pointcut {
+	return func(fn FunctionInfo) bool {
+		return slices.Contains(names, fn.Name)
+	}
+}
+
+// Matching selects functions whose name matches a path.Match glob pattern such as "User*".
+func Matching(pattern string) Pointcut {
+	return func(fn FunctionInfo) bool {
+		matched, err := path.Match(pattern, fn.Name)
+		return err == nil && matched
+	}
+}
+
+// Tagged selects functions registered with the given tag value.
+func Tagged(key, value string) Pointcut {
+	return func(fn FunctionInfo) bool {
+		tagValue, ok := fn.Tags[key]
+		return ok && tagValue == value
+	}
+}
+
+// All selects every registered function.
+func All() Pointcut {
+	return func(fn FunctionInfo) bool {
+		return true
+	}
+}
+
+// And selects functions matched by both pointcuts.
+func (pointcut Pointcut) And(other Pointcut) Pointcut {
+	return func(fn FunctionInfo) bool {
+		return pointcut(fn) && other(fn)
+	}
+}
+
+// Or selects functions matched by either pointcut.
+func (pointcut Pointcut) Or(other Pointcut) Pointcut {
+	return func(fn FunctionInfo) bool {
+		return pointcut(fn) || other(fn)
+	}
+}
+
+// Not selects functions not matched by the pointcut.
+func (pointcut Pointcut) Not() Pointcut {
+	return func(fn FunctionInfo) bool {
+		return !pointcut(fn)
+	}
+}
+
+// Tags returns a copy of the tags of a registered function.
+// Returns nil if the function is not registered or has no tags.
+func (registry *Registry) Tags(functionName string) map[string]string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	chain, exists := registry.entries[functionName]
+	if !exists || chain.tags == nil {
+		return nil
+	}
+	return maps.Clone(chain.tags)
+}
+
+// Select returns the names of registered functions matched by the pointcut, sorted.
+func (registry *Registry) Select(pointcut Pointcut) []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	var names []string
+	for name, chain := range registry.entries {
+		if pointcut(FunctionInfo{Name: name, Tags: chain.tags}) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// Tags returns the tags of a function in the global registry.
+func Tags(functionName string) map[string]string {
+	return globalRegistry.Tags(functionName)
+}
+
+// Select returns functions of the global registry matched by the pointcut.
+func Select(pointcut Pointcut) []string {
+	return globalRegistry.Select(pointcut)
+}
@@ -0,0 +1,169 @@
+// Package profiling - profiling provides an aspect running wrapped calls under runtime/pprof labels,
+// so CPU and goroutine profiles can be sliced by function (go tool pprof -tagfocus function=Name)
+package profiling
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// FunctionLabel is the pprof label holding the registered function name.
+const FunctionLabel = "function"
+
+const defaultName = "profiling"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Config configures a Profiler. Zero values fall back to defaults.
+type Config struct {
+	Name     string   // Name of the advice, for runtime switches (default "profiling").
+	Priority int      // Priority of the Around advice; keep it highest so other advice is attributed too.
+	Tags     []string // Tags are registration tag keys (aspect.WithTags) added as labels when set, e.g. "team".
+
+	// Labels adds label key-value pairs derived from a call, as for pprof.Labels (optional).
+	Labels func(ctx *aspect.Context) []string
+}
+
+// Profiler is an aspect labelling the goroutine of each call with its function name and tags.
+// Goroutines started by the call inherit the labels. Functions taking a context.Context as first
+// argument receive one carrying the labels, so nested wrapped calls keep their caller's labels.
+type Profiler struct {
+	config Config
+
+	mu           sync.RWMutex
+	registries   []*aspect.Registry
+	tags         map[string][]string // tags caches the Tags label pairs read from registries.
+	unsubscribes []func()
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Profiler, filling unset configuration with defaults.
+func New(config Config) *Profiler {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	return &Profiler{config: config, tags: make(map[string][]string)}
+}
+
+// Name returns the advice name.
+func (profiler *Profiler) Name() string {
+	return profiler.config.Name
+}
+
+// Advice returns the Around advice labelling the call.
+func (profiler *Profiler) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     profiler.config.Name,
+			Type:     aspect.Around,
+			Priority: profiler.config.Priority,
+			Handler:  profiler.around,
+		},
+	}
+}
+
+// Attach implements aspect.RegistryAware; Tags labels read the registrations of its functions.
+func (profiler *Profiler) Attach(registry *aspect.Registry) {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+
+	profiler.registries = append(profiler.registries, registry)
+	profiler.unsubscribes = append(profiler.unsubscribes, registry.Subscribe(profiler.forget))
+}
+
+// Init implements aspect.Aspect; every setting has a default.
+func (profiler *Profiler) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; it stops following registry events.
+func (profiler *Profiler) Close() error {
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+
+	for _, unsubscribe := range profiler.unsubscribes {
+		unsubscribe()
+	}
+	profiler.unsubscribes = nil
+	return nil
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around proceeds with the call's labels added to those of the goroutine.
+func (profiler *Profiler) around(ctx *aspect.Context) error {
+	parent := ctx.Context()
+	ctx.DoLabeled(pprof.Labels(profiler.labels(ctx)...), func(labelled context.Context) {
+		if len(ctx.Args) > 0 {
+			if _, acceptsContext := ctx.Args[0].(context.Context); acceptsContext {
+				ctx.Args[0] = labelled
+				defer func() { ctx.Args[0] = parent }()
+			}
+		}
+		_ = ctx.Proceed()
+	})
+	return nil
+}
+
+// labels returns the label key-value pairs of a call.
+func (profiler *Profiler) labels(ctx *aspect.Context) []string {
+	labels := []string{FunctionLabel, ctx.FunctionName}
+	if len(profiler.config.Tags) > 0 {
+		labels = append(labels, profiler.tagLabels(ctx.FunctionName)...)
+	}
+	if profiler.config.Labels != nil {
+		labels = append(labels, profiler.config.Labels(ctx)...)
+	}
+	return labels
+}
+
+// tagLabels returns the Tags label pairs of a function from the first attached registry that has
+// it, caching them since tags are fixed at registration.
+func (profiler *Profiler) tagLabels(functionName string) []string {
+	profiler.mu.RLock()
+	cached, exists := profiler.tags[functionName]
+	registries := profiler.registries
+	profiler.mu.RUnlock()
+	if exists {
+		return cached
+	}
+
+	for _, registry := range registries {
+		if !registry.IsRegistered(functionName) {
+			continue
+		}
+		tags := registry.Tags(functionName)
+		resolved := make([]string, 0, 2*len(profiler.config.Tags))
+		for _, key := range profiler.config.Tags {
+			if value, ok := tags[key]; ok {
+				resolved = append(resolved, key, value)
+			}
+		}
+		profiler.mu.Lock()
+		profiler.tags[functionName] = resolved
+		profiler.mu.Unlock()
+		return resolved
+	}
+	return nil
+}
+
+// forget drops cached tags when a function may have been registered again with other tags.
+func (profiler *Profiler) forget(event aspect.Event) {
+	if event.Type != aspect.FunctionUnregistered && event.Type != aspect.RegistryCleared {
+		return
+	}
+
+	profiler.mu.Lock()
+	defer profiler.mu.Unlock()
+	if event.Type == aspect.RegistryCleared {
+		clear(profiler.tags)
+		return
+	}
+	delete(profiler.tags, event.FunctionName)
+}
@@ -0,0 +1,100 @@
+// Package profiling - profiling_test validates the pprof labels seen by wrapped calls
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies the profiler.
+func setup(t *testing.T, profiler *Profiler, names ...string) {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name, aspect.WithTags(map[string]string{"team": "search", "tier": "gold"}))
+	}
+	registry.MustApply(aspect.On(names...), profiler)
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestProfiler_ContextLabels(t *testing.T) {
+	setup(t, New(Config{
+		Tags: []string{"team", "missing"},
+		Labels: func(ctx *aspect.Context) []string {
+			if query, ok := ctx.Metadata["query"].(string); ok {
+				return []string{"query", query}
+			}
+			return nil
+		},
+	}), "Search", "Rank")
+
+	labels := make(map[string]string)
+	rank := aspect.Wrap1("Rank", func(ctx context.Context) {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+	})
+	search := aspect.Wrap2("Search", func(ctx context.Context, query string) { rank(ctx) })
+
+	aspect.MustAddAdvice("Search", aspect.Advice{
+		Type:    aspect.Before,
+		Handler: func(ctx *aspect.Context) error { ctx.Metadata["query"] = ctx.Args[1]; return nil },
+	})
+	search(context.Background(), "golang")
+
+	if labels[FunctionLabel] != "Rank" || labels["team"] != "search" || labels["query"] != "golang" {
+		t.Fatalf("expected nested call labels on top of its caller's, got %v", labels)
+	}
+	if _, ok := labels["tier"]; ok {
+		t.Error("expected unselected tags to stay out of the labels")
+	}
+}
+
+func TestProfiler_GoroutineLabels(t *testing.T) {
+	setup(t, New(Config{}), "Busy")
+
+	var profile bytes.Buffer
+	busy := aspect.Wrap0("Busy", func() {
+		_ = pprof.Lookup("goroutine").WriteTo(&profile, 1)
+	})
+	busy()
+
+	if !strings.Contains(profile.String(), `"function":"Busy"`) {
+		t.Fatalf("expected the goroutine profile to carry the function label, got:\n%s", profile.String())
+	}
+}
+
+func TestProfiler_TagsFollowReregistration(t *testing.T) {
+	profiler := New(Config{Tags: []string{"team"}})
+	setup(t, profiler, "Index")
+
+	var teams []string
+	index := aspect.Wrap1("Index", func(ctx context.Context) {
+		team, _ := pprof.Label(ctx, "team")
+		teams = append(teams, team)
+	})
+	index(context.Background())
+
+	registry := aspect.GetGlobalRegistry()
+	registry.MustUnregister("Index")
+	registry.MustRegister("Index", aspect.WithTags(map[string]string{"team": "ranking"}))
+	registry.MustApply(aspect.On("Index"), profiler)
+	index(context.Background())
+
+	if len(teams) != 2 || teams[0] != "search" || teams[1] != "ranking" {
+		t.Fatalf("expected the team tag of each registration, got %v", teams)
+	}
+}
@@ -0,0 +1,126 @@
+// Package ratelimit - limiter defines token-bucket and sliding-window limiters
+package ratelimit
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Limiter grants permits for a single key. Implementations must be safe for concurrent use.
+type Limiter interface {
+	// Allow takes a permit if one is available at now; otherwise it returns false and
+	// the time until one may become available.
+	Allow(now time.Time) (ok bool, retryAfter time.Duration)
+}
+
+// LimiterFactory creates the Limiter of a new key.
+type LimiterFactory func() Limiter
+
+// tokenBucket refills rate tokens per second up to burst.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// slidingWindow allows limit calls in any window, remembering the times of the last limit calls.
+type slidingWindow struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	times  []time.Time // times is a ring buffer of the last calls, oldest at next.
+	next   int
+}
+
+// validator is implemented by the built-in limiters to reject unusable settings in Init.
+type validator interface {
+	validate() error
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// TokenBucket allows bursts of up to burst calls, refilled at rate calls per second.
+// Init rejects a rate or burst that is not positive.
+func TokenBucket(rate float64, burst int) LimiterFactory {
+	return func() Limiter {
+		return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+	}
+}
+
+// SlidingWindow allows at most limit calls within any window.
+// Init rejects a limit or window that is not positive.
+func SlidingWindow(limit int, window time.Duration) LimiterFactory {
+	return func() Limiter {
+		return &slidingWindow{limit: limit, window: window, times: make([]time.Time, 0, max(limit, 0))}
+	}
+}
+
+// Allow implements Limiter.
+func (bucket *tokenBucket) Allow(now time.Time) (bool, time.Duration) {
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	if !bucket.last.IsZero() && now.After(bucket.last) {
+		bucket.tokens = math.Min(bucket.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate)
+	}
+	if now.After(bucket.last) {
+		bucket.last = now
+	}
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+	if bucket.rate <= 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	return false, time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+}
+
+// validate rejects a bucket that never grants or never refills permits.
+func (bucket *tokenBucket) validate() error {
+	if bucket.rate <= 0 || math.IsNaN(bucket.rate) {
+		return errors.New("token bucket rate must be positive")
+	}
+	if bucket.burst < 1 {
+		return errors.New("token bucket burst must be positive")
+	}
+	return nil
+}
+
+// Allow implements Limiter.
+func (window *slidingWindow) Allow(now time.Time) (bool, time.Duration) {
+	window.mu.Lock()
+	defer window.mu.Unlock()
+
+	limit := cap(window.times)
+	if limit == 0 {
+		return false, time.Duration(math.MaxInt64)
+	}
+	if len(window.times) < limit {
+		window.times = append(window.times, now)
+		return true, 0
+	}
+
+	oldest := window.times[window.next]
+	if reopenAt := oldest.Add(window.window); now.Before(reopenAt) {
+		return false, reopenAt.Sub(now)
+	}
+	window.times[window.next] = now
+	window.next = (window.next + 1) % limit
+	return true, 0
+}
+
+// validate rejects a window that never grants permits.
+func (window *slidingWindow) validate() error {
+	if window.limit <= 0 || window.window <= 0 {
+		return errors.New("sliding window limit and window must be positive")
+	}
+	return nil
+}
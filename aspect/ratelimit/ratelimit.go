@@ -0,0 +1,274 @@
+// Package ratelimit - ratelimit provides an aspect throttling calls globally, per function or per key
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	Reject Mode = iota // Reject fails calls over the limit immediately with ErrRateLimited.
+	Wait               // Wait delays calls over the limit until a permit is available.
+)
+
+const (
+	defaultName        = "rate-limit"
+	defaultIdleTimeout = 10 * time.Minute
+	minSweepSize       = 64
+)
+
+// ErrRateLimited is matched (via errors.Is) by errors returned for calls rejected by a limiter.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Mode selects what happens to calls over the limit.
+type Mode int
+
+// KeyFunc derives the limiter key of a call; calls sharing a key share a limiter.
+type KeyFunc func(ctx *aspect.Context) string
+
+// Clock provides the current time; inject a fake in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// LimitError is the error set on calls rejected by a limiter.
+type LimitError struct {
+	Name       string        // Name is the rate limit aspect name.
+	Key        string        // Key identifies the limiter that rejected the call.
+	RetryAfter time.Duration // RetryAfter is the time until a permit may be available.
+}
+
+// Config configures a RateLimiter. Zero values fall back to defaults.
+type Config struct {
+	Name     string         // Name of the advice, for runtime switches (default "rate-limit").
+	Priority int            // Priority of the Around advice.
+	Limiter  LimiterFactory // Limiter creates the limiter of each key (required), e.g. TokenBucket(10, 20).
+	Mode     Mode           // Mode selects rejecting or waiting (default Reject).
+	MaxWait  time.Duration  // MaxWait rejects waiting calls needing longer (default: wait until the call's context is done).
+
+	// IdleTimeout forgets the limiter of a key unused for that long, bounding memory when keys are
+	// per tenant or per argument (default 10m). It should exceed the time a limiter takes to refill,
+	// since a forgotten key starts again with a full allowance.
+	IdleTimeout time.Duration
+
+	// KeyFunc derives the limiter key (default PerFunction).
+	KeyFunc KeyFunc
+	// Clock provides the time (default: the system clock).
+	Clock Clock
+	// Sleep waits in Wait mode and must return early with an error when ctx is done
+	// (default: a timer honoring ctx).
+	Sleep func(ctx context.Context, delay time.Duration) error
+}
+
+// RateLimiter is an aspect holding one limiter per key.
+type RateLimiter struct {
+	config Config
+
+	mu        sync.Mutex
+	limiters  map[string]*keyLimiter
+	sweepSize int // sweepSize is the number of limiters that triggers the next sweep of idle ones.
+}
+
+// keyLimiter is the limiter of a key and the time it was last used.
+type keyLimiter struct {
+	Limiter
+	lastUsed time.Time
+}
+
+// systemClock reads the system time.
+type systemClock struct{}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a RateLimiter, filling unset configuration with defaults.
+func New(config Config) *RateLimiter {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = PerFunction
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	if config.Sleep == nil {
+		config.Sleep = sleep
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultIdleTimeout
+	}
+	return &RateLimiter{config: config, limiters: make(map[string]*keyLimiter), sweepSize: minSweepSize}
+}
+
+// Name returns the advice name.
+func (limiter *RateLimiter) Name() string {
+	return limiter.config.Name
+}
+
+// Advice returns the Around advice throttling calls.
+func (limiter *RateLimiter) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     limiter.config.Name,
+			Type:     aspect.Around,
+			Priority: limiter.config.Priority,
+			Handler:  limiter.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (limiter *RateLimiter) Init() error {
+	if limiter.config.Limiter == nil {
+		return fmt.Errorf("rate limiter '%s': limiter is required", limiter.config.Name)
+	}
+	if probe, ok := limiter.config.Limiter().(validator); ok {
+		if err := probe.validate(); err != nil {
+			return fmt.Errorf("rate limiter '%s': %w", limiter.config.Name, err)
+		}
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; it forgets all limiters.
+func (limiter *RateLimiter) Close() error {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.limiters = make(map[string]*keyLimiter)
+	return nil
+}
+
+// Keys returns the number of keys with a live limiter.
+func (limiter *RateLimiter) Keys() int {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return len(limiter.limiters)
+}
+
+// Reset forgets the limiter of key, granting it a full allowance.
+func (limiter *RateLimiter) Reset(key string) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	delete(limiter.limiters, key)
+}
+
+// PerFunction keys calls by function name, one limit per function.
+func PerFunction(ctx *aspect.Context) string {
+	return ctx.FunctionName
+}
+
+// Global keys every call the same, one limit shared by all functions the aspect is applied to.
+func Global(ctx *aspect.Context) string {
+	return ""
+}
+
+// ByArg keys calls by the argument at index (e.g. a tenant ID), within each function.
+func ByArg(index int) KeyFunc {
+	return func(ctx *aspect.Context) string {
+		if index >= len(ctx.Args) {
+			return ctx.FunctionName
+		}
+		return fmt.Sprintf("%s:%v", ctx.FunctionName, ctx.Args[index])
+	}
+}
+
+// ByMetadata keys calls by a metadata value set by earlier advice (e.g. the authenticated user), within each function.
+func ByMetadata(key string) KeyFunc {
+	return func(ctx *aspect.Context) string {
+		return fmt.Sprintf("%s:%v", ctx.FunctionName, ctx.Metadata[key])
+	}
+}
+
+// Error implements the error interface.
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("rate limit '%s' exceeded for '%s', retry in %v", err.Name, err.Key, err.RetryAfter)
+}
+
+// Unwrap makes LimitError match ErrRateLimited.
+func (err *LimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// Now returns the system time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around admits the call, waits for a permit, or rejects it with a LimitError.
+func (limiter *RateLimiter) around(ctx *aspect.Context) error {
+	key := limiter.config.KeyFunc(ctx)
+	keyLimiter := limiter.limiter(key, limiter.config.Clock.Now())
+
+	for {
+		ok, retryAfter := keyLimiter.Allow(limiter.config.Clock.Now())
+		if ok {
+			_ = ctx.Proceed()
+			return nil
+		}
+
+		limitErr := &LimitError{Name: limiter.config.Name, Key: key, RetryAfter: retryAfter}
+		if limiter.config.Mode != Wait || (limiter.config.MaxWait > 0 && retryAfter > limiter.config.MaxWait) {
+			ctx.Error = limitErr
+			ctx.Skipped = true
+			return nil
+		}
+
+		if err := limiter.config.Sleep(ctx.Context(), retryAfter); err != nil {
+			ctx.Error = errors.Join(limitErr, err)
+			ctx.Skipped = true
+			return nil
+		}
+	}
+}
+
+// limiter returns the limiter of key, creating it on first use and sweeping idle limiters
+// whenever their number has doubled since the last sweep.
+func (limiter *RateLimiter) limiter(key string, now time.Time) Limiter {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	existing, exists := limiter.limiters[key]
+	if !exists {
+		if len(limiter.limiters) >= limiter.sweepSize {
+			limiter.sweep(now)
+		}
+		existing = &keyLimiter{Limiter: limiter.config.Limiter()}
+		limiter.limiters[key] = existing
+	}
+	existing.lastUsed = now
+	return existing.Limiter
+}
+
+// sweep forgets limiters idle for longer than IdleTimeout. Caller holds limiter.mu.
+func (limiter *RateLimiter) sweep(now time.Time) {
+	for key, existing := range limiter.limiters {
+		if now.Sub(existing.lastUsed) > limiter.config.IdleTimeout {
+			delete(limiter.limiters, key)
+		}
+	}
+	limiter.sweepSize = max(2*len(limiter.limiters), minSweepSize)
+}
+
+// sleep waits for delay or until ctx is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
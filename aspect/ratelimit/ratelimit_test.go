@@ -0,0 +1,188 @@
+// Package ratelimit - ratelimit_test validates limiters, keys and wait/reject modes
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time { return clock.now }
+
+// setup registers functions on a fresh global registry and applies the rate limiter to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestRateLimiter_RejectsOverLimit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	setup(t, New(Config{Limiter: TokenBucket(1, 2), Clock: clock}), "CallExternalService")
+
+	calls := 0
+	call := aspect.Wrap1RE("CallExternalService", func(endpoint string) (string, error) {
+		calls++
+		return "ok", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := call("/api"); err != nil {
+			t.Fatalf("call %d: expected burst to be allowed, got %v", i, err)
+		}
+	}
+
+	_, err := call("/api")
+	var limitErr *LimitError
+	if !errors.Is(err, ErrRateLimited) || !errors.As(err, &limitErr) {
+		t.Fatalf("expected ErrRateLimited, got %v", err)
+	}
+	if limitErr.RetryAfter != time.Second || calls != 2 {
+		t.Fatalf("expected 1s retry-after without calling the target, got %v and %d calls", limitErr.RetryAfter, calls)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	if _, err := call("/api"); err != nil {
+		t.Fatalf("expected refilled token, got %v", err)
+	}
+}
+
+func TestRateLimiter_WaitsForPermit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	var delays []time.Duration
+	setup(t, New(Config{
+		Limiter: TokenBucket(10, 1),
+		Mode:    Wait,
+		Clock:   clock,
+		Sleep: func(ctx context.Context, delay time.Duration) error {
+			delays = append(delays, delay)
+			clock.now = clock.now.Add(delay)
+			return nil
+		},
+	}), "Send")
+
+	send := aspect.Wrap0RE("Send", func() (int, error) { return 1, nil })
+	for i := 0; i < 3; i++ {
+		if _, err := send(); err != nil {
+			t.Fatalf("call %d: expected waiting call to succeed, got %v", i, err)
+		}
+	}
+	if len(delays) != 2 || delays[0] != 100*time.Millisecond {
+		t.Fatalf("expected two 100ms waits, got %v", delays)
+	}
+}
+
+func TestRateLimiter_WaitHonorsContextAndMaxWait(t *testing.T) {
+	setup(t, New(Config{Limiter: TokenBucket(0.001, 1), Mode: Wait}), "Fetch")
+	fetch := aspect.Wrap1E("Fetch", func(ctx context.Context) error { return nil })
+
+	_ = fetch(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := fetch(ctx); !errors.Is(err, ErrRateLimited) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected rate limited and deadline errors, got %v", err)
+	}
+
+	setup(t, New(Config{Limiter: TokenBucket(0.001, 1), Mode: Wait, MaxWait: time.Second}), "Bounded")
+	bounded := aspect.Wrap0RE("Bounded", func() (int, error) { return 1, nil })
+	_, _ = bounded()
+	if _, err := bounded(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected rejection beyond MaxWait, got %v", err)
+	}
+}
+
+func TestRateLimiter_PerKeyLimits(t *testing.T) {
+	registry := setup(t, New(Config{Limiter: SlidingWindow(1, time.Minute), KeyFunc: ByMetadata("tenant")}), "Query")
+	registry.MustAddAdvice("Query", aspect.Advice{
+		Type: aspect.Before,
+		Handler: func(ctx *aspect.Context) error {
+			ctx.Metadata["tenant"] = ctx.Args[0]
+			return nil
+		},
+	})
+
+	query := aspect.Wrap1E("Query", func(tenant string) error { return nil })
+
+	if err := query("acme"); err != nil {
+		t.Fatalf("expected first acme call to pass, got %v", err)
+	}
+	if err := query("globex"); err != nil {
+		t.Fatalf("expected other tenant unaffected, got %v", err)
+	}
+	if err := query("acme"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected second acme call to be limited, got %v", err)
+	}
+}
+
+func TestRateLimiter_ForgetsIdleKeys(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	limiter := New(Config{Limiter: TokenBucket(1, 1), KeyFunc: ByArg(0), IdleTimeout: time.Minute, Clock: clock})
+	setup(t, limiter, "Lookup")
+	lookup := aspect.Wrap1E("Lookup", func(id int) error { return nil })
+
+	for id := range minSweepSize {
+		_ = lookup(id)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	_ = lookup(0)
+
+	// The next new key finds minSweepSize limiters and sweeps all but the one just used
+	_ = lookup(minSweepSize)
+	if keys := limiter.Keys(); keys != 2 {
+		t.Fatalf("expected idle limiters to be forgotten, got %d keys", keys)
+	}
+}
+
+func TestRateLimiter_InitValidatesLimiter(t *testing.T) {
+	for name, factory := range map[string]LimiterFactory{
+		"missing":          nil,
+		"zero rate":        TokenBucket(0, 5),
+		"zero burst":       TokenBucket(1, 0),
+		"negative limit":   SlidingWindow(-1, time.Second),
+		"zero window size": SlidingWindow(5, 0),
+	} {
+		if err := New(Config{Limiter: factory}).Init(); err == nil {
+			t.Errorf("%s: expected Init to fail", name)
+		}
+	}
+	if err := New(Config{Limiter: TokenBucket(0.5, 1)}).Init(); err != nil {
+		t.Fatalf("expected a valid token bucket, got %v", err)
+	}
+}
+
+func TestSlidingWindow_Allow(t *testing.T) {
+	limiter := SlidingWindow(2, 10*time.Second)()
+	start := time.Unix(1_000, 0)
+
+	limiter.Allow(start)
+	limiter.Allow(start.Add(4 * time.Second))
+	if ok, retryAfter := limiter.Allow(start.Add(5 * time.Second)); ok || retryAfter != 5*time.Second {
+		t.Fatalf("expected rejection for 5s, got ok=%v retryAfter=%v", ok, retryAfter)
+	}
+	if ok, _ := limiter.Allow(start.Add(10 * time.Second)); !ok {
+		t.Fatal("expected a permit once the oldest call left the window")
+	}
+	if ok, retryAfter := limiter.Allow(start.Add(11 * time.Second)); ok || retryAfter != 3*time.Second {
+		t.Fatalf("expected rejection until the second call leaves the window, got ok=%v retryAfter=%v", ok, retryAfter)
+	}
+}
@@ -0,0 +1,287 @@
+// Package recorder - recorder provides a flight recorder keeping the last invocations of each
+// function in bounded memory, dumped as JSON on demand and when a call panics
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	defaultName           = "recorder"
+	defaultSize           = 100
+	defaultMaxValueLength = 256
+)
+
+// Metadata keys of the call being recorded.
+const (
+	startKey    = "recorder.start"
+	recordedKey = "recorder.recorded"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Record summarizes a single invocation.
+type Record struct {
+	Function string        `json:"function"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`          // Duration is in nanoseconds.
+	Args     []string      `json:"args,omitempty"`    // Args summarize the aspect.Redacted arguments.
+	Results  []string      `json:"results,omitempty"` // Results summarize the aspect.Redacted results.
+	Error    string        `json:"error,omitempty"`
+	Panic    string        `json:"panic,omitempty"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Degraded bool          `json:"degraded,omitempty"`
+	Advice   []string      `json:"advice,omitempty"` // Advice lists the advice that ran, as "Type name (priority)".
+}
+
+// Config configures a Recorder. Zero values fall back to defaults.
+type Config struct {
+	Name           string // Name of the advice, for runtime switches (default "recorder").
+	Size           int    // Size is the number of invocations kept per function (default 100).
+	MaxValueLength int    // MaxValueLength truncates argument and result summaries (default 256 bytes).
+
+	// PanicDump receives a JSON dump of the panicking function's records (default os.Stderr).
+	PanicDump io.Writer
+	// NoPanicDump disables the automatic dump on panic.
+	NoPanicDump bool
+}
+
+// Recorder is an aspect recording every invocation of the functions it is applied to. Its Before
+// advice runs first and its After advice last, so the records list the rest of the chain.
+type Recorder struct {
+	config Config
+
+	mu    sync.RWMutex
+	rings map[string]*ring
+	dump  sync.Mutex
+}
+
+// ring keeps the last records of a function.
+type ring struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Recorder, filling unset configuration with defaults.
+func New(config Config) *Recorder {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.Size <= 0 {
+		config.Size = defaultSize
+	}
+	if config.MaxValueLength <= 0 {
+		config.MaxValueLength = defaultMaxValueLength
+	}
+	if config.PanicDump == nil {
+		config.PanicDump = os.Stderr
+	}
+	return &Recorder{config: config, rings: make(map[string]*ring)}
+}
+
+// Name returns the advice name.
+func (recorder *Recorder) Name() string {
+	return recorder.config.Name
+}
+
+// Advice returns the Before advice starting the record, the After advice storing it and the
+// AfterThrowing advice storing it and dumping the function's records.
+func (recorder *Recorder) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{Name: recorder.config.Name, Type: aspect.Before, Priority: math.MaxInt, Handler: recorder.before},
+		{Name: recorder.config.Name, Type: aspect.AfterThrowing, Priority: math.MinInt, Handler: recorder.afterThrowing},
+		{Name: recorder.config.Name, Type: aspect.After, Priority: math.MinInt, Handler: recorder.after},
+	}
+}
+
+// Init implements aspect.Aspect; every setting has a default.
+func (recorder *Recorder) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; records stay readable.
+func (recorder *Recorder) Close() error {
+	return nil
+}
+
+// Records returns the kept records of a function, oldest first.
+func (recorder *Recorder) Records(functionName string) []Record {
+	recorder.mu.RLock()
+	functionRing, exists := recorder.rings[functionName]
+	recorder.mu.RUnlock()
+
+	if !exists {
+		return nil
+	}
+	return functionRing.snapshot()
+}
+
+// Dump writes the records of the given functions (every recorded function if none) as a JSON
+// object mapping function names to their records, oldest first.
+func (recorder *Recorder) Dump(writer io.Writer, functionNames ...string) error {
+	if len(functionNames) == 0 {
+		recorder.mu.RLock()
+		for functionName := range recorder.rings {
+			functionNames = append(functionNames, functionName)
+		}
+		recorder.mu.RUnlock()
+		sort.Strings(functionNames)
+	}
+
+	dump := make(map[string][]Record, len(functionNames))
+	for _, functionName := range functionNames {
+		dump[functionName] = recorder.Records(functionName)
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(dump)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// before starts timing the call and tracking the advice run for it.
+func (recorder *Recorder) before(ctx *aspect.Context) error {
+	ctx.Metadata[startKey] = time.Now()
+	ctx.TrackAdvice()
+	return nil
+}
+
+// afterThrowing records the panicking call and dumps its function's records.
+func (recorder *Recorder) afterThrowing(ctx *aspect.Context) error {
+	recorder.record(ctx)
+	if recorder.config.NoPanicDump {
+		return nil
+	}
+
+	// Serialize dumps of concurrent panics so they do not interleave
+	recorder.dump.Lock()
+	defer recorder.dump.Unlock()
+	_ = recorder.Dump(recorder.config.PanicDump, ctx.FunctionName)
+	return nil
+}
+
+// after records the call unless AfterThrowing already did.
+func (recorder *Recorder) after(ctx *aspect.Context) error {
+	if recorded, _ := ctx.Metadata[recordedKey].(bool); !recorded {
+		recorder.record(ctx)
+	}
+	return nil
+}
+
+// record summarizes the call into its function's ring.
+func (recorder *Recorder) record(ctx *aspect.Context) {
+	ctx.Metadata[recordedKey] = true
+	start, _ := ctx.Metadata[startKey].(time.Time)
+	values := aspect.Redacted(ctx)
+
+	record := Record{
+		Function: ctx.FunctionName,
+		Start:    start,
+		Duration: time.Since(start),
+		Args:     recorder.summarize(values.Args),
+		Results:  recorder.summarize(values.Results),
+		Skipped:  ctx.Skipped,
+		Degraded: ctx.Degraded,
+	}
+	if ctx.Error != nil {
+		record.Error = recorder.truncate(ctx.Error.Error())
+	}
+	if ctx.HasPanic() {
+		record.Panic = recorder.truncate(fmt.Sprintf("%v", ctx.PanicValue))
+		record.Results = nil
+	}
+	for _, advice := range ctx.ExecutedAdvice() {
+		if advice.Name == recorder.config.Name {
+			continue
+		}
+		record.Advice = append(record.Advice, fmt.Sprintf("%s %s (%d)", advice.Type, advice.Name, advice.Priority))
+	}
+
+	recorder.ring(ctx.FunctionName).add(record, recorder.config.Size)
+}
+
+// summarize formats values with %v, truncated.
+func (recorder *Recorder) summarize(values []any) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	summaries := make([]string, len(values))
+	for index, value := range values {
+		if value == aspect.RedactedMask {
+			summaries[index] = aspect.RedactedMask
+			continue
+		}
+		summaries[index] = recorder.truncate(fmt.Sprintf("%v", value))
+	}
+	return summaries
+}
+
+// truncate cuts text to at most MaxValueLength bytes, without splitting a UTF-8 character.
+func (recorder *Recorder) truncate(text string) string {
+	if len(text) <= recorder.config.MaxValueLength {
+		return text
+	}
+	cut := recorder.config.MaxValueLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "..."
+}
+
+// ring returns the ring of a function, creating it on first use.
+func (recorder *Recorder) ring(functionName string) *ring {
+	recorder.mu.RLock()
+	existing, exists := recorder.rings[functionName]
+	recorder.mu.RUnlock()
+	if exists {
+		return existing
+	}
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if existing, exists := recorder.rings[functionName]; exists {
+		return existing
+	}
+	created := &ring{}
+	recorder.rings[functionName] = created
+	return created
+}
+
+// add stores a record, overwriting the oldest once size records are kept.
+func (functionRing *ring) add(record Record, size int) {
+	functionRing.mu.Lock()
+	defer functionRing.mu.Unlock()
+
+	if len(functionRing.records) < size {
+		functionRing.records = append(functionRing.records, record)
+		return
+	}
+	functionRing.records[functionRing.next] = record
+	functionRing.next = (functionRing.next + 1) % size
+}
+
+// snapshot returns the records oldest first.
+func (functionRing *ring) snapshot() []Record {
+	functionRing.mu.Lock()
+	defer functionRing.mu.Unlock()
+
+	records := make([]Record, 0, len(functionRing.records))
+	records = append(records, functionRing.records[functionRing.next:]...)
+	return append(records, functionRing.records[:functionRing.next]...)
+}
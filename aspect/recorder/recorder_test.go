@@ -0,0 +1,110 @@
+// Package recorder - recorder_test validates the flight recorder rings, dumps and panic dumps
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies the recorder.
+func setup(t *testing.T, recorder *Recorder, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name, aspect.WithRedactedArgs(1))
+	}
+	registry.MustApply(aspect.On(names...), recorder)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestRecorder_KeepsLastInvocations(t *testing.T) {
+	recorder := New(Config{Size: 3, MaxValueLength: 6})
+	registry := setup(t, recorder, "Login")
+	registry.MustAddAdvice("Login", aspect.Advice{Name: "audit", Type: aspect.Before, Priority: 10, Handler: func(ctx *aspect.Context) error { return nil }})
+
+	login := aspect.Wrap2RE("Login", func(user, password string) (string, error) {
+		if user == "mallory" {
+			return "", errors.New("denied")
+		}
+		return "token-for-" + user, nil
+	})
+	for _, user := range []string{"alice", "bob", "carol", "mallory"} {
+		_, _ = login(user, "hunter2")
+	}
+
+	records := recorder.Records("Login")
+	if len(records) != 3 || records[0].Args[0] != "bob" || records[2].Args[0] != "mallor..." {
+		t.Fatalf("expected the last 3 invocations oldest first with truncated args, got %+v", records)
+	}
+	if records[0].Args[1] != aspect.RedactedMask || records[0].Results[0] != "token-..." {
+		t.Errorf("expected redacted and truncated summaries, got %+v", records[0])
+	}
+	if records[2].Error != "denied" {
+		t.Errorf("expected the error to be recorded, got %+v", records[2])
+	}
+	if len(records[0].Advice) != 1 || records[0].Advice[0] != "Before audit (10)" {
+		t.Errorf("expected the other advice to be listed, got %v", records[0].Advice)
+	}
+}
+
+func TestRecorder_TruncatesAtCharacterBoundaries(t *testing.T) {
+	recorder := New(Config{MaxValueLength: 6})
+	for text, want := range map[string]string{
+		"tissué":  "tissu...", // é spans bytes 6-7: the cut would split it
+		"日本語テキスト": "日本...",    // 3-byte characters: the third one would be split
+		"short":   "short",
+	} {
+		if got := recorder.truncate(text); got != want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestRecorder_DumpAndPanicDump(t *testing.T) {
+	var panicDump bytes.Buffer
+	recorder := New(Config{PanicDump: &panicDump})
+	setup(t, recorder, "Parse", "Other")
+
+	parse := aspect.Wrap1R("Parse", func(input string) int {
+		if input == "" {
+			panic("empty input")
+		}
+		return len(input)
+	})
+	aspect.Wrap0("Other", func() {})()
+	parse("abc")
+	func() {
+		defer func() { _ = recover() }()
+		parse("")
+	}()
+
+	var dumped map[string][]Record
+	if err := json.Unmarshal(panicDump.Bytes(), &dumped); err != nil {
+		t.Fatalf("expected a JSON panic dump, got %v:\n%s", err, panicDump.String())
+	}
+	if _, others := dumped["Other"]; others || len(dumped["Parse"]) != 2 || dumped["Parse"][1].Panic != "empty input" {
+		t.Fatalf("expected the panicking function's records including the panic, got %+v", dumped)
+	}
+	if len(recorder.Records("Parse")) != 2 {
+		t.Error("expected the panicking call to be recorded once")
+	}
+
+	var all bytes.Buffer
+	if err := recorder.Dump(&all); err != nil || !strings.Contains(all.String(), `"Other"`) {
+		t.Fatalf("expected every function in the full dump, got %v:\n%s", err, all.String())
+	}
+}
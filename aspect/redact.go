@@ -0,0 +1,274 @@
+// Package aspect - redact masks sensitive arguments and results before advice records them
+package aspect
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// RedactedMask replaces redacted values and fields.
+const RedactedMask = "[REDACTED]"
+
+// CycleMask replaces a pointer, map or slice met again while it is being redacted.
+const CycleMask = "[CYCLE]"
+
+// redactTag is the struct tag value (`aspect:"redact"`) marking a field as sensitive.
+const redactTag = "redact"
+
+// sensitiveTypes caches, per reflect.Type, whether values of the type can hold redacted fields.
+var sensitiveTypes sync.Map
+
+// -------------------------------------------- Types --------------------------------------------
+
+// RedactedValues is a copy of a call's arguments and results that is safe to log or store.
+type RedactedValues struct {
+	Args    []any // Args are the arguments, with redacted parameters replaced by RedactedMask.
+	Results []any // Results are the results, with redacted results replaced by RedactedMask.
+}
+
+// redaction holds the parameter and result positions redacted for a function.
+type redaction struct {
+	args    []int
+	results []int
+}
+
+// redactor rebuilds sensitive values, remembering the pointers, maps and slices it visited so that
+// shared values are redacted once and cyclic values terminate.
+type redactor struct {
+	visited map[visit]redactedValue
+}
+
+// redactedValue is the outcome of redacting a value; unmasked values are the original ones.
+type redactedValue struct {
+	value  any
+	masked bool
+}
+
+// visit identifies a pointer, map or slice value; the type and length tell apart values sharing an address.
+type visit struct {
+	address   uintptr
+	valueType reflect.Type
+	length    int
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// WithRedactedArgs redacts the arguments at the given positions, e.g. passwords and tokens,
+// in the Redacted view of every call of the function.
+func WithRedactedArgs(indices ...int) RegisterOption {
+	return func(chain *AdviceChain) {
+		chain.redaction.args = append(chain.redaction.args, indices...)
+	}
+}
+
+// WithRedactedResults redacts the results at the given positions in the Redacted view of every call of the function.
+func WithRedactedResults(indices ...int) RegisterOption {
+	return func(chain *AdviceChain) {
+		chain.redaction.results = append(chain.redaction.results, indices...)
+	}
+}
+
+// Redacted returns the arguments and results of a call as advice should record them: positions
+// redacted at registration become RedactedMask, and structs with fields tagged `aspect:"redact"`
+// become maps of their exported fields with those fields masked, including structs held in interface
+// values. Other values are returned as is, and values referencing themselves are cut with CycleMask.
+func Redacted(ctx *Context) RedactedValues {
+	return RedactedValues{
+		Args:    redactValues(ctx.Args, ctx.redaction.args),
+		Results: redactValues(ctx.Results, ctx.redaction.results),
+	}
+}
+
+// RedactValue masks the fields tagged `aspect:"redact"` in value, see Redacted.
+func RedactValue(value any) any {
+	if value == nil || !sensitive(reflect.TypeOf(value)) {
+		return value
+	}
+	redactor := &redactor{visited: make(map[visit]redactedValue)}
+	return redactor.redact(reflect.ValueOf(value)).value
+}
+
+// RedactedArgs returns the argument positions redacted at the registration of a function, see WithRedactedArgs.
+func (registry *Registry) RedactedArgs(functionName string) []int {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	chain, exists := registry.entries[functionName]
+	if !exists {
+		return nil
+	}
+	return slices.Clone(chain.redaction.args)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// redactValues copies values, masking the given positions and tagged fields.
+func redactValues(values []any, masked []int) []any {
+	redacted := make([]any, len(values))
+	for index, value := range values {
+		if slices.Contains(masked, index) {
+			redacted[index] = RedactedMask
+			continue
+		}
+		redacted[index] = RedactValue(value)
+	}
+	return redacted
+}
+
+// sensitive reports whether values of a type can hold fields tagged for redaction.
+func sensitive(valueType reflect.Type) bool {
+	if cached, ok := sensitiveTypes.Load(valueType); ok {
+		return cached.(bool)
+	}
+	result := inspect(valueType, make(map[reflect.Type]struct{}))
+	sensitiveTypes.Store(valueType, result)
+	return result
+}
+
+// inspect walks a type looking for fields tagged for redaction, skipping types already being visited.
+// Interfaces may hold any value, so their dynamic values are checked when redacting.
+func inspect(valueType reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if _, seen := visiting[valueType]; seen {
+		return false
+	}
+	visiting[valueType] = struct{}{}
+
+	switch valueType.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return inspect(valueType.Elem(), visiting)
+	case reflect.Struct:
+		for index := range valueType.NumField() {
+			field := valueType.Field(index)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Tag.Get("aspect") == redactTag || inspect(field.Type, visiting) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redact rebuilds a sensitive value with its tagged fields masked, returning the original value
+// when nothing in it was masked.
+func (redactor *redactor) redact(value reflect.Value) redactedValue {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() || !sensitive(value.Elem().Type()) {
+			return original(value)
+		}
+		return redactor.redact(value.Elem())
+	case reflect.Pointer:
+		if value.IsNil() {
+			return original(value)
+		}
+		return redactor.once(value, 0, func() redactedValue {
+			if element := redactor.redact(value.Elem()); element.masked {
+				return element
+			}
+			return original(value)
+		})
+	case reflect.Slice:
+		if value.IsNil() {
+			return original(value)
+		}
+		return redactor.once(value, value.Len(), func() redactedValue { return redactor.items(value) })
+	case reflect.Array:
+		return redactor.items(value)
+	case reflect.Map:
+		if value.IsNil() {
+			return original(value)
+		}
+		return redactor.once(value, 0, func() redactedValue { return redactor.entries(value) })
+	case reflect.Struct:
+		if sensitive(value.Type()) {
+			return redactor.fields(value)
+		}
+	}
+	return original(value)
+}
+
+// items redacts the elements of a slice or array.
+func (redactor *redactor) items(value reflect.Value) redactedValue {
+	items := make([]any, value.Len())
+	masked := false
+	for index := range items {
+		item := redactor.redact(value.Index(index))
+		items[index], masked = item.value, masked || item.masked
+	}
+	if !masked {
+		return original(value)
+	}
+	return redactedValue{value: items, masked: true}
+}
+
+// entries redacts the values of a map.
+func (redactor *redactor) entries(value reflect.Value) redactedValue {
+	entries := make(map[any]any, value.Len())
+	masked := false
+	for iterator := value.MapRange(); iterator.Next(); {
+		entry := redactor.redact(iterator.Value())
+		entries[iterator.Key().Interface()], masked = entry.value, masked || entry.masked
+	}
+	if !masked {
+		return original(value)
+	}
+	return redactedValue{value: entries, masked: true}
+}
+
+// fields redacts the exported fields of a struct into a map.
+func (redactor *redactor) fields(value reflect.Value) redactedValue {
+	fields := make(map[string]any, value.NumField())
+	masked := false
+	for index := range value.NumField() {
+		field := value.Type().Field(index)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Tag.Get("aspect") == redactTag {
+			fields[field.Name], masked = RedactedMask, true
+			continue
+		}
+		redacted := redactor.redact(value.Field(index))
+		fields[field.Name], masked = redacted.value, masked || redacted.masked
+	}
+	if !masked {
+		return original(value)
+	}
+	return redactedValue{value: fields, masked: true}
+}
+
+// once redacts a pointer, map or slice a single time: a value met again while it is being redacted
+// is a cycle and becomes CycleMask, one met again afterwards reuses its redacted copy.
+func (redactor *redactor) once(value reflect.Value, length int, redact func() redactedValue) redactedValue {
+	key := visit{address: value.Pointer(), valueType: value.Type(), length: length}
+	if redacted, seen := redactor.visited[key]; seen {
+		return redacted
+	}
+	redactor.visited[key] = redactedValue{value: CycleMask, masked: true}
+
+	redacted := redact()
+	redactor.visited[key] = redacted
+	return redacted
+}
+
+// original returns a value unchanged.
+func original(value reflect.Value) redactedValue {
+	if !value.IsValid() || !value.CanInterface() {
+		return redactedValue{}
+	}
+	return redactedValue{value: value.Interface()}
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// RedactedArgs returns the redacted argument positions of a function in the global registry.
+func RedactedArgs(functionName string) []int {
+	return globalRegistry.RedactedArgs(functionName)
+}
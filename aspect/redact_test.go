@@ -0,0 +1,111 @@
+// Package aspect - redact_test validates redaction rules and struct tag masking
+package aspect
+
+import "testing"
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// credentials is a struct with fields tagged for redaction.
+type credentials struct {
+	Username string
+	Password string `aspect:"redact"`
+	Profile  *profile
+}
+
+// profile nests a tagged field inside a tagged struct.
+type profile struct {
+	Email string
+	Token string `aspect:"redact"`
+}
+
+// linkedCredentials can reference itself.
+type linkedCredentials struct {
+	Password string `aspect:"redact"`
+	Next     *linkedCredentials
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestRedacted_RegistrationRules(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Login", WithRedactedArgs(1), WithRedactedResults(0))
+
+	var seen RedactedValues
+	registry.MustAddAdvice("Login", Advice{
+		Type: After,
+		Handler: func(ctx *Context) error {
+			seen = Redacted(ctx)
+			return nil
+		},
+	})
+
+	login := Wrap2RE("Login", func(user, password string) (string, error) { return "session-token", nil })
+	if session, err := login("alice", "hunter2"); err != nil || session != "session-token" {
+		t.Fatalf("expected redaction to leave the call untouched, got (%q, %v)", session, err)
+	}
+
+	if seen.Args[0] != "alice" || seen.Args[1] != RedactedMask {
+		t.Errorf("expected password argument to be redacted, got %v", seen.Args)
+	}
+	if seen.Results[0] != RedactedMask {
+		t.Errorf("expected session result to be redacted, got %v", seen.Results)
+	}
+}
+
+func TestRedactValue_StructTags(t *testing.T) {
+	value := credentials{Username: "alice", Password: "hunter2", Profile: &profile{Email: "a@example.com", Token: "t0k3n"}}
+
+	redacted, ok := RedactValue(value).(map[string]any)
+	if !ok {
+		t.Fatalf("expected tagged struct to be rendered as a map, got %T", RedactValue(value))
+	}
+	if redacted["Username"] != "alice" || redacted["Password"] != RedactedMask {
+		t.Errorf("unexpected redacted fields %v", redacted)
+	}
+	if nested := redacted["Profile"].(map[string]any); nested["Email"] != "a@example.com" || nested["Token"] != RedactedMask {
+		t.Errorf("expected nested tagged field to be redacted, got %v", nested)
+	}
+	if value.Password != "hunter2" {
+		t.Error("expected the original value to stay untouched")
+	}
+
+	if items := RedactValue([]credentials{value}).([]any); items[0].(map[string]any)["Password"] != RedactedMask {
+		t.Errorf("expected slice elements to be redacted, got %v", items)
+	}
+	if untagged := (struct{ Name string }{"x"}); RedactValue(untagged) != untagged {
+		t.Error("expected untagged values to be returned as is")
+	}
+}
+
+func TestRedactValue_CyclicValues(t *testing.T) {
+	node := &linkedCredentials{Password: "hunter2"}
+	node.Next = node
+	shared := &profile{Token: "t0k3n"}
+
+	redacted := RedactValue(node).(map[string]any)
+	if redacted["Password"] != RedactedMask || redacted["Next"] != CycleMask {
+		t.Errorf("expected the cycle to be cut after redaction, got %v", redacted)
+	}
+
+	items := RedactValue([]*profile{shared, shared}).([]any)
+	if first, second := items[0].(map[string]any), items[1].(map[string]any); first["Token"] != RedactedMask || second["Token"] != RedactedMask {
+		t.Errorf("expected a shared value to be redacted at every position, got %v", items)
+	}
+}
+
+func TestRedactValue_InterfaceValues(t *testing.T) {
+	value := credentials{Username: "alice", Password: "hunter2"}
+
+	redacted := RedactValue(map[string]any{"user": value, "attempt": 1}).(map[any]any)
+	if redacted["user"].(map[string]any)["Password"] != RedactedMask || redacted["attempt"] != 1 {
+		t.Errorf("expected a tagged struct inside map[string]any to be redacted, got %v", redacted)
+	}
+	if items := RedactValue([]any{"x", &value}).([]any); items[0] != "x" || items[1].(map[string]any)["Password"] != RedactedMask {
+		t.Errorf("expected a tagged struct inside []any to be redacted, got %v", items)
+	}
+
+	plain := map[string]any{"user": "alice"}
+	if redacted, ok := RedactValue(plain).(map[string]any); !ok || redacted["user"] != "alice" {
+		t.Errorf("expected interface values without tagged fields to be returned as is, got %v", RedactValue(plain))
+	}
+}
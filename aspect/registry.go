@@ -2,8 +2,10 @@
 package aspect
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // -------------------------------------------- Constants & Variables --------------------------------------------
@@ -11,26 +13,49 @@ import (
 // globalRegistry is the default registry instance.
 var globalRegistry = NewRegistry()
 
+// ErrRegistryFrozen is returned by mutating operations after Freeze has been called.
+var ErrRegistryFrozen = errors.New("registry is frozen")
+
 // -------------------------------------------- Types --------------------------------------------
 
 // Registry stores function references and their associated advice chains.
 type Registry struct {
-	mu      sync.RWMutex
-	entries map[string]*AdviceChain
+	mu       sync.RWMutex
+	entries  map[string]*AdviceChain
+	switches *switchboard
+	frozen   atomic.Bool
+	events   broadcaster[Event]
+
+	active    atomic.Int64  // active counts in-flight invocations across all functions.
+	closing   atomic.Bool   // closing rejects new invocations once Shutdown begins.
+	idle      chan struct{} // idle wakes Shutdown when the last invocation finishes.
+	closeOnce sync.Once
+
+	aspectMu sync.Mutex
+	aspects  []Aspect // aspects are the initialized aspects, closed on Shutdown.
+
+	gaugeMu sync.RWMutex
+	gauges  map[gaugeKey]Gauge
 }
 
 // NewRegistry creates a new empty registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		entries: make(map[string]*AdviceChain),
+		entries:  make(map[string]*AdviceChain),
+		switches: newSwitchboard(),
+		idle:     make(chan struct{}, 1),
+		gauges:   make(map[gaugeKey]Gauge),
 	}
 }
 
 // -------------------------------------------- Public Functions --------------------------------------------
 
-// Register registers a function with the given name.
+// Register registers a function with the given name and options (e.g. WithTags).
 // Returns error if the function is already registered.
-func (registry *Registry) Register(name string) error {
+func (registry *Registry) Register(name string, opts ...RegisterOption) error {
+	events := registry.batch()
+	defer events.flush()
+
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
@@ -38,17 +63,27 @@ func (registry *Registry) Register(name string) error {
 		return fmt.Errorf("function name cannot be empty")
 	}
 
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
 	if _, exists := registry.entries[name]; exists {
 		return fmt.Errorf("function '%s' is already registered", name)
 	}
 
-	registry.entries[name] = NewAdviceChain()
+	registry.entries[name] = registry.newChain(opts)
+	events.add(Event{Type: FunctionRegistered, FunctionName: name})
 	return nil
 }
 
-// RegisterOrGet registers a function if not already registered, otherwise returns existing chain.
-// Always returns the advice chain and never errors.
-func (registry *Registry) RegisterOrGet(name string) *AdviceChain {
+// RegisterOrGet registers a function if not already registered, otherwise returns existing chain
+// (options only apply on first registration).
+// Always returns the advice chain and never errors; panics with ErrRegistryFrozen if
+// a new function would be registered on a frozen registry.
+func (registry *Registry) RegisterOrGet(name string, opts ...RegisterOption) *AdviceChain {
+	events := registry.batch()
+	defer events.flush()
+
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
@@ -60,15 +95,20 @@ func (registry *Registry) RegisterOrGet(name string) *AdviceChain {
 		return chain
 	}
 
-	chain := NewAdviceChain()
+	if registry.frozen.Load() {
+		panic(ErrRegistryFrozen)
+	}
+
+	chain := registry.newChain(opts)
 	registry.entries[name] = chain
+	events.add(Event{Type: FunctionRegistered, FunctionName: name})
 	return chain
 }
 
 // MustRegister registers a function and panics on error.
 // Useful for initialization code where registration must succeed.
-func (registry *Registry) MustRegister(name string) {
-	if err := registry.Register(name); err != nil {
+func (registry *Registry) MustRegister(name string, opts ...RegisterOption) {
+	if err := registry.Register(name, opts...); err != nil {
 		panic(err)
 	}
 }
@@ -76,6 +116,9 @@ func (registry *Registry) MustRegister(name string) {
 // AddAdvice adds an advice to the specified function.
 // Returns error if the function is not registered.
 func (registry *Registry) AddAdvice(functionName string, advice Advice) error {
+	events := registry.batch()
+	defer events.flush()
+
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
 
@@ -83,12 +126,17 @@ func (registry *Registry) AddAdvice(functionName string, advice Advice) error {
 		return fmt.Errorf("function name cannot be empty")
 	}
 
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
 	chain, exists := registry.entries[functionName]
 	if !exists {
 		return fmt.Errorf("function '%s' is not registered", functionName)
 	}
 
 	chain.Add(advice)
+	events.add(Event{Type: AdviceAdded, FunctionName: functionName, AdviceName: advice.Name, AdviceType: advice.Type})
 	return nil
 }
 
@@ -100,6 +148,46 @@ func (registry *Registry) MustAddAdvice(functionName string, advice Advice) {
 	}
 }
 
+// RemoveAdvice detaches every advice with the given name from the specified function.
+// Returns error if the function is not registered, no advice has that name, or the registry is frozen.
+func (registry *Registry) RemoveAdvice(functionName, adviceName string) error {
+	events := registry.batch()
+	defer events.flush()
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if functionName == "" || adviceName == "" {
+		return fmt.Errorf("function name and advice name cannot be empty")
+	}
+
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
+	chain, exists := registry.entries[functionName]
+	if !exists {
+		return fmt.Errorf("function '%s' is not registered", functionName)
+	}
+
+	removed := chain.Remove(adviceName)
+	if len(removed) == 0 {
+		return fmt.Errorf("advice '%s' is not attached to function '%s'", adviceName, functionName)
+	}
+
+	for _, advice := range removed {
+		events.add(Event{Type: AdviceRemoved, FunctionName: functionName, AdviceName: advice.Name, AdviceType: advice.Type})
+	}
+	return nil
+}
+
+// MustRemoveAdvice removes advice and panics on error.
+func (registry *Registry) MustRemoveAdvice(functionName, adviceName string) {
+	if err := registry.RemoveAdvice(functionName, adviceName); err != nil {
+		panic(err)
+	}
+}
+
 // GetAdviceChain retrieves the advice chain for a function.
 // Returns error if the function is not registered.
 func (registry *Registry) GetAdviceChain(functionName string) (*AdviceChain, error) {
@@ -128,11 +216,30 @@ func (registry *Registry) IsRegistered(name string) bool {
 }
 
 // Unregister removes a function from the registry.
-// Does nothing if the function is not registered.
-func (registry *Registry) Unregister(name string) {
+// Does nothing if the function is not registered; returns ErrRegistryFrozen if frozen.
+func (registry *Registry) Unregister(name string) error {
+	events := registry.batch()
+	defer events.flush()
+
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
-	delete(registry.entries, name)
+
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
+	if _, exists := registry.entries[name]; exists {
+		delete(registry.entries, name)
+		events.add(Event{Type: FunctionUnregistered, FunctionName: name})
+	}
+	return nil
+}
+
+// MustUnregister removes a function and panics on error.
+func (registry *Registry) MustUnregister(name string) {
+	if err := registry.Unregister(name); err != nil {
+		panic(err)
+	}
 }
 
 // ListRegistered returns all registered function names.
@@ -148,10 +255,51 @@ func (registry *Registry) ListRegistered() []string {
 }
 
 // Clear removes all registered functions from the registry.
-func (registry *Registry) Clear() {
+// Returns ErrRegistryFrozen if the registry is frozen.
+func (registry *Registry) Clear() error {
+	events := registry.batch()
+	defer events.flush()
+
 	registry.mu.Lock()
 	defer registry.mu.Unlock()
+
+	if registry.frozen.Load() {
+		return ErrRegistryFrozen
+	}
+
 	registry.entries = make(map[string]*AdviceChain)
+	events.add(Event{Type: RegistryCleared})
+	return nil
+}
+
+// MustClear removes all registered functions and panics on error.
+func (registry *Registry) MustClear() {
+	if err := registry.Clear(); err != nil {
+		panic(err)
+	}
+}
+
+// Freeze seals the registry: Register, AddAdvice, Unregister and Clear return
+// ErrRegistryFrozen from now on. Every advice chain is precompiled so invocations
+// never sort or copy advice lists. Freezing is permanent and idempotent.
+func (registry *Registry) Freeze() {
+	events := registry.batch()
+	defer events.flush()
+
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, chain := range registry.entries {
+		chain.freeze()
+	}
+	if !registry.frozen.Swap(true) {
+		events.add(Event{Type: RegistryFrozen})
+	}
+}
+
+// IsFrozen returns true once Freeze has been called.
+func (registry *Registry) IsFrozen() bool {
+	return registry.frozen.Load()
 }
 
 // Count returns the number of registered functions.
@@ -175,21 +323,33 @@ func (registry *Registry) GetAdviceCount(functionName string) int {
 	return chain.Count()
 }
 
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// newChain creates an advice chain bound to the registry's runtime switches.
+func (registry *Registry) newChain(opts []RegisterOption) *AdviceChain {
+	chain := NewAdviceChain()
+	chain.switches = registry.switches
+	for _, opt := range opts {
+		opt(chain)
+	}
+	return chain
+}
+
 // -------------------------------------------- Global Registry Functions --------------------------------------------
 
 // Register registers a function in the global registry.
-func Register(name string) error {
-	return globalRegistry.Register(name)
+func Register(name string, opts ...RegisterOption) error {
+	return globalRegistry.Register(name, opts...)
 }
 
 // RegisterOrGet registers/gets a function in the global registry.
-func RegisterOrGet(name string) *AdviceChain {
-	return globalRegistry.RegisterOrGet(name)
+func RegisterOrGet(name string, opts ...RegisterOption) *AdviceChain {
+	return globalRegistry.RegisterOrGet(name, opts...)
 }
 
 // MustRegister registers a function in the global registry and panics on error.
-func MustRegister(name string) {
-	globalRegistry.MustRegister(name)
+func MustRegister(name string, opts ...RegisterOption) {
+	globalRegistry.MustRegister(name, opts...)
 }
 
 // AddAdvice adds advice to a function in the global registry.
@@ -202,6 +362,16 @@ func MustAddAdvice(functionName string, advice Advice) {
 	globalRegistry.MustAddAdvice(functionName, advice)
 }
 
+// RemoveAdvice removes named advice from a function in the global registry.
+func RemoveAdvice(functionName, adviceName string) error {
+	return globalRegistry.RemoveAdvice(functionName, adviceName)
+}
+
+// MustRemoveAdvice removes named advice from the global registry and panics on error.
+func MustRemoveAdvice(functionName, adviceName string) {
+	globalRegistry.MustRemoveAdvice(functionName, adviceName)
+}
+
 // GetAdviceChain retrieves advice chain from the global registry.
 func GetAdviceChain(functionName string) (*AdviceChain, error) {
 	return globalRegistry.GetAdviceChain(functionName)
@@ -213,8 +383,13 @@ func IsRegistered(name string) bool {
 }
 
 // Unregister removes a function from the global registry.
-func Unregister(name string) {
-	globalRegistry.Unregister(name)
+func Unregister(name string) error {
+	return globalRegistry.Unregister(name)
+}
+
+// MustUnregister removes a function from the global registry and panics on error.
+func MustUnregister(name string) {
+	globalRegistry.MustUnregister(name)
 }
 
 // ListRegistered returns all registered function names from the global registry.
@@ -223,8 +398,23 @@ func ListRegistered() []string {
 }
 
 // Clear removes all registered functions from the global registry.
-func Clear() {
-	globalRegistry.Clear()
+func Clear() error {
+	return globalRegistry.Clear()
+}
+
+// MustClear removes all registered functions from the global registry and panics on error.
+func MustClear() {
+	globalRegistry.MustClear()
+}
+
+// Freeze seals the global registry against further mutation.
+func Freeze() {
+	globalRegistry.Freeze()
+}
+
+// IsFrozen returns true if the global registry is frozen.
+func IsFrozen() bool {
+	return globalRegistry.IsFrozen()
 }
 
 // Count returns the number of registered functions in the global registry.
@@ -2,6 +2,7 @@
 package aspect
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -284,6 +285,59 @@ func TestRegistry_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 }
 
+func TestRegistry_Freeze(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Frozen")
+	registry.MustAddAdvice("Frozen", Advice{
+		Type:     Before,
+		Priority: 100,
+		Handler:  func(ctx *Context) error { return nil },
+	})
+
+	registry.Freeze()
+
+	if !registry.IsFrozen() {
+		t.Fatal("expected registry to be frozen")
+	}
+	if err := registry.Register("Another"); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen from Register, got %v", err)
+	}
+	if err := registry.AddAdvice("Frozen", Advice{Type: After, Handler: func(ctx *Context) error { return nil }}); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen from AddAdvice, got %v", err)
+	}
+	if err := registry.Unregister("Frozen"); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen from Unregister, got %v", err)
+	}
+	if err := registry.Clear(); !errors.Is(err, ErrRegistryFrozen) {
+		t.Fatalf("expected ErrRegistryFrozen from Clear, got %v", err)
+	}
+
+	// Existing entries stay readable and unchanged
+	if registry.GetAdviceCount("Frozen") != 1 {
+		t.Fatalf("expected 1 advice, got %d", registry.GetAdviceCount("Frozen"))
+	}
+	if registry.RegisterOrGet("Frozen") == nil {
+		t.Fatal("expected existing chain from RegisterOrGet")
+	}
+
+	// Must variants and direct chain mutation panic
+	for name, mutate := range map[string]func(){
+		"MustRegister":  func() { registry.MustRegister("Another") },
+		"MustAddAdvice": func() { registry.MustAddAdvice("Frozen", Advice{Type: Before}) },
+		"RegisterOrGet": func() { registry.RegisterOrGet("Another") },
+		"ChainAdd":      func() { registry.RegisterOrGet("Frozen").Add(Advice{Type: Before}) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r != ErrRegistryFrozen {
+					t.Errorf("%s: expected panic with ErrRegistryFrozen, got %v", name, r)
+				}
+			}()
+			mutate()
+		}()
+	}
+}
+
 func TestGlobalRegistry_Functions(t *testing.T) {
 	// Clear global registry before test
 	Clear()
@@ -0,0 +1,68 @@
+// Package retry - backoff provides delay policies between retry attempts
+package retry
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Backoff returns the delay before the given retry (1 for the first retry),
+// given the delay used before the previous retry (0 for the first retry).
+type Backoff func(retry int, previous time.Duration) time.Duration
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// Constant waits the same delay before every retry.
+func Constant(delay time.Duration) Backoff {
+	return func(retry int, previous time.Duration) time.Duration {
+		return delay
+	}
+}
+
+// Exponential doubles the delay on every retry: base, 2*base, 4*base... capped at ceiling.
+func Exponential(base, ceiling time.Duration) Backoff {
+	return func(retry int, previous time.Duration) time.Duration {
+		return exponentialDelay(base, ceiling, retry)
+	}
+}
+
+// ExponentialJitter draws a random delay in [0, exponential delay] ("full jitter"),
+// spreading retries of concurrent callers.
+func ExponentialJitter(base, ceiling time.Duration) Backoff {
+	return func(retry int, previous time.Duration) time.Duration {
+		return randomBetween(0, exponentialDelay(base, ceiling, retry))
+	}
+}
+
+// Decorrelated draws a random delay in [base, 3*previous] capped at ceiling ("decorrelated jitter"),
+// growing delays without synchronizing concurrent callers.
+func Decorrelated(base, ceiling time.Duration) Backoff {
+	return func(retry int, previous time.Duration) time.Duration {
+		if previous < base {
+			previous = base
+		}
+		return min(ceiling, randomBetween(base, 3*previous))
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// exponentialDelay computes base * 2^(retry-1) without overflowing, capped at ceiling.
+func exponentialDelay(base, ceiling time.Duration, retry int) time.Duration {
+	multiplier := math.Pow(2, float64(retry-1))
+	if delay := float64(base) * multiplier; delay < float64(ceiling) {
+		return time.Duration(delay)
+	}
+	return ceiling
+}
+
+// randomBetween returns a uniformly random duration in [low, high].
+func randomBetween(low, high time.Duration) time.Duration {
+	if high <= low {
+		return low
+	}
+	return low + rand.N(high-low+1)
+}
@@ -0,0 +1,187 @@
+// Package retry - retry provides Around advice that re-invokes failing wrapped functions with backoff
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// AttemptsKey is the aspect.Context metadata key holding the number of attempts made so far.
+const AttemptsKey = "retry.attempts"
+
+const (
+	defaultName        = "retry"
+	defaultMaxAttempts = 3
+	defaultBaseDelay   = 100 * time.Millisecond
+	defaultMaxDelay    = 10 * time.Second
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Classifier reports whether an error is worth retrying.
+type Classifier func(err error) bool
+
+// Config configures a Retrier. Zero values fall back to defaults.
+type Config struct {
+	Name        string     // Name of the advice, for runtime switches (default "retry").
+	Priority    int        // Priority of the Around advice; higher wraps advice with lower priority.
+	MaxAttempts int        // MaxAttempts is the total number of attempts, including the first (default 3).
+	Backoff     Backoff    // Backoff computes delays between attempts (default Exponential(100ms, 10s)).
+	RetryIf     Classifier // RetryIf selects retryable errors (default: every error).
+
+	// OnRetry is called before sleeping ahead of each retry (optional).
+	OnRetry func(ctx *aspect.Context, attempt int, err error, delay time.Duration)
+	// Sleep waits between attempts and must return early with an error when ctx is done
+	// (default: a timer honoring ctx).
+	Sleep func(ctx context.Context, delay time.Duration) error
+}
+
+// Retrier is an aspect that retries failed invocations of the functions it is applied to.
+type Retrier struct {
+	config Config
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Retrier, filling unset configuration with defaults.
+func New(config Config) *Retrier {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.Backoff == nil {
+		config.Backoff = Exponential(defaultBaseDelay, defaultMaxDelay)
+	}
+	if config.RetryIf == nil {
+		config.RetryIf = func(err error) bool { return true }
+	}
+	if config.Sleep == nil {
+		config.Sleep = sleep
+	}
+	return &Retrier{config: config}
+}
+
+// Name returns the advice name.
+func (retrier *Retrier) Name() string {
+	return retrier.config.Name
+}
+
+// Advice returns the Around advice performing the retries.
+func (retrier *Retrier) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     retrier.config.Name,
+			Type:     aspect.Around,
+			Priority: retrier.config.Priority,
+			Handler:  retrier.around,
+		},
+	}
+}
+
+// Init implements aspect.Aspect; a Retrier holds no state.
+func (retrier *Retrier) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; a Retrier holds no state.
+func (retrier *Retrier) Close() error {
+	return nil
+}
+
+// Attempts returns the number of attempts recorded on the context (0 if retry advice did not run).
+func Attempts(ctx *aspect.Context) int {
+	attempts, _ := ctx.Metadata[AttemptsKey].(int)
+	return attempts
+}
+
+// OnErrors retries errors matching any of the targets via errors.Is.
+func OnErrors(targets ...error) Classifier {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// OnErrorType retries errors with an error of type T in their chain via errors.As.
+func OnErrorType[T error]() Classifier {
+	return func(err error) bool {
+		var target T
+		return errors.As(err, &target)
+	}
+}
+
+// AnyOf retries errors accepted by any classifier.
+func AnyOf(classifiers ...Classifier) Classifier {
+	return func(err error) bool {
+		for _, classifier := range classifiers {
+			if classifier(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not retries errors rejected by the classifier, e.g. Not(OnErrors(ErrInvalidInput)).
+func Not(classifier Classifier) Classifier {
+	return func(err error) bool {
+		return !classifier(err)
+	}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around re-invokes the rest of the chain until it succeeds, the error is not retryable,
+// attempts are exhausted, or the call's context is done.
+func (retrier *Retrier) around(ctx *aspect.Context) error {
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		ctx.Metadata[AttemptsKey] = attempt
+		if attempt > 1 {
+			// A rejection by inner advice (rate limit, open circuit, full bulkhead) skips the target
+			// and sets the error; start each retry clean so a later attempt can run it
+			ctx.Skipped = false
+			ctx.Error = nil
+			ctx.Results = make([]any, 0)
+		}
+
+		err := ctx.Proceed()
+		if err == nil || attempt >= retrier.config.MaxAttempts || !retrier.config.RetryIf(err) {
+			return nil
+		}
+
+		delay = retrier.config.Backoff(attempt, delay)
+		if retrier.config.OnRetry != nil {
+			retrier.config.OnRetry(ctx, attempt, err, delay)
+		}
+
+		if sleepErr := retrier.config.Sleep(ctx.Context(), delay); sleepErr != nil {
+			ctx.Error = errors.Join(err, sleepErr)
+			return nil
+		}
+	}
+}
+
+// sleep waits for delay or until ctx is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
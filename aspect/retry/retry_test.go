@@ -0,0 +1,243 @@
+// Package retry - retry_test validates retry advice and backoff policies
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/circuitbreaker"
+	"github.com/seyedali-dev/gosaidsno/aspect/ratelimit"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+var (
+	errTransient = errors.New("transient")
+	errPermanent = errors.New("permanent")
+)
+
+// timeoutError is a typed error used to exercise OnErrorType.
+type timeoutError struct{}
+
+func (timeoutError) Error() string { return "timeout" }
+
+// fakeClock is a manually advanced clock shared with the rate limiter and circuit breaker.
+type fakeClock struct {
+	now time.Time
+}
+
+func (clock *fakeClock) Now() time.Time { return clock.now }
+
+// advance returns a Sleep advancing the clock instead of sleeping.
+func (clock *fakeClock) advance(ctx context.Context, delay time.Duration) error {
+	clock.now = clock.now.Add(delay)
+	return nil
+}
+
+// noSleep records delays instead of sleeping.
+func noSleep(delays *[]time.Duration) func(context.Context, time.Duration) error {
+	return func(ctx context.Context, delay time.Duration) error {
+		*delays = append(*delays, delay)
+		return nil
+	}
+}
+
+// setup registers functions on a fresh global registry and applies the retrier to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestRetry_SucceedsAfterTransientFailures(t *testing.T) {
+	var delays []time.Duration
+	var recordedAttempts int
+	retrier := New(Config{
+		MaxAttempts: 5,
+		Backoff:     Exponential(10*time.Millisecond, time.Second),
+		Sleep:       noSleep(&delays),
+	})
+	registry := setup(t, retrier, "SendEmail")
+	registry.MustAddAdvice("SendEmail", aspect.Advice{
+		Type: aspect.After,
+		Handler: func(ctx *aspect.Context) error {
+			recordedAttempts = Attempts(ctx)
+			return nil
+		},
+	})
+
+	calls := 0
+	sendEmail := aspect.Wrap1E("SendEmail", func(to string) error {
+		calls++
+		if calls < 3 {
+			return errTransient
+		}
+		return nil
+	})
+
+	if err := sendEmail("user@example.com"); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if calls != 3 || recordedAttempts != 3 {
+		t.Fatalf("expected 3 calls and attempts, got calls=%d attempts=%d", calls, recordedAttempts)
+	}
+	if len(delays) != 2 || delays[0] != 10*time.Millisecond || delays[1] != 20*time.Millisecond {
+		t.Fatalf("expected exponential delays [10ms 20ms], got %v", delays)
+	}
+}
+
+func TestRetry_ExhaustsAttempts(t *testing.T) {
+	var delays []time.Duration
+	setup(t, New(Config{MaxAttempts: 3, Sleep: noSleep(&delays)}), "AlwaysFails")
+
+	calls := 0
+	alwaysFails := aspect.Wrap0RE("AlwaysFails", func() (string, error) {
+		calls++
+		return "", errTransient
+	})
+
+	if _, err := alwaysFails(); !errors.Is(err, errTransient) {
+		t.Fatalf("expected last error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_ClassifiesErrors(t *testing.T) {
+	var delays []time.Duration
+	setup(t, New(Config{
+		MaxAttempts: 5,
+		RetryIf:     AnyOf(OnErrors(errTransient), OnErrorType[timeoutError]()),
+		Sleep:       noSleep(&delays),
+	}), "Classified")
+
+	failures := []error{errTransient, timeoutError{}, errPermanent}
+	calls := 0
+	classified := aspect.Wrap0RE("Classified", func() (int, error) {
+		err := failures[calls]
+		calls++
+		return 0, err
+	})
+
+	if _, err := classified(); !errors.Is(err, errPermanent) {
+		t.Fatalf("expected permanent error, got %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected to stop at the permanent error after 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_StopsWhenContextDone(t *testing.T) {
+	setup(t, New(Config{MaxAttempts: 10, Backoff: Constant(time.Hour)}), "Cancelled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	cancelled := aspect.Wrap1E("Cancelled", func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errTransient
+	})
+
+	err := cancelled(ctx)
+	if !errors.Is(err, errTransient) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected transient and canceled errors, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retry after cancellation, got %d calls", calls)
+	}
+}
+
+func TestRetry_OverRateLimitRejection(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	registry := setup(t, New(Config{
+		Priority:    100,
+		MaxAttempts: 3,
+		Backoff:     Constant(time.Second),
+		RetryIf:     OnErrors(ratelimit.ErrRateLimited),
+		Sleep:       clock.advance,
+	}), "CallPartner")
+	registry.MustApply(aspect.On("CallPartner"), ratelimit.New(ratelimit.Config{Priority: 50, Limiter: ratelimit.TokenBucket(1, 1), Clock: clock}))
+
+	calls := 0
+	callPartner := aspect.Wrap0RE("CallPartner", func() (string, error) {
+		calls++
+		return "ok", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if result, err := callPartner(); err != nil || result != "ok" {
+			t.Fatalf("call %d: expected the retry to get a permit, got (%q, %v)", i, result, err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected the target to run on both calls, got %d", calls)
+	}
+}
+
+func TestRetry_OverOpenCircuit(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1_000, 0)}
+	registry := setup(t, New(Config{
+		Priority:    100,
+		MaxAttempts: 3,
+		Backoff:     Constant(time.Second),
+		Sleep:       clock.advance,
+	}), "ChargeCard")
+	registry.MustApply(aspect.On("ChargeCard"), circuitbreaker.New(circuitbreaker.Config{
+		Priority:    50,
+		Policy:      circuitbreaker.ConsecutiveFailures(1),
+		OpenTimeout: 1500 * time.Millisecond,
+		Clock:       clock,
+	}))
+
+	calls := 0
+	chargeCard := aspect.Wrap0RE("ChargeCard", func() (string, error) {
+		calls++
+		if calls == 1 {
+			return "", errTransient
+		}
+		return "charged", nil
+	})
+
+	// Attempt 1 fails and opens the circuit, attempt 2 is rejected, attempt 3 probes and succeeds
+	if result, err := chargeCard(); err != nil || result != "charged" {
+		t.Fatalf("expected the probe to succeed, got (%q, %v)", result, err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected the target to run on attempts 1 and 3, got %d calls", calls)
+	}
+}
+
+func TestBackoff_Policies(t *testing.T) {
+	if delay := Exponential(time.Second, 5*time.Second)(10, 0); delay != 5*time.Second {
+		t.Errorf("expected exponential delay capped at 5s, got %v", delay)
+	}
+
+	for retry := 1; retry <= 20; retry++ {
+		if delay := ExponentialJitter(time.Millisecond, time.Second)(retry, 0); delay < 0 || delay > time.Second {
+			t.Fatalf("jitter delay out of range: %v", delay)
+		}
+	}
+
+	previous := time.Duration(0)
+	for retry := 1; retry <= 20; retry++ {
+		delay := Decorrelated(10*time.Millisecond, time.Second)(retry, previous)
+		if delay < 10*time.Millisecond || delay > time.Second {
+			t.Fatalf("decorrelated delay out of range: %v", delay)
+		}
+		previous = delay
+	}
+}
@@ -0,0 +1,173 @@
+// Package aspect - shutdown tracks in-flight invocations and drains them on graceful shutdown
+package aspect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ErrShutdown is set as the Context error of calls rejected after Shutdown has begun.
+// Wrappers with an error return surface it; wrappers without one panic with it, as with any rejection.
+var ErrShutdown = errors.New("registry is shutting down")
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// InFlight returns the number of active invocations per registered function.
+func (registry *Registry) InFlight() map[string]int64 {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	inflight := make(map[string]int64, len(registry.entries))
+	for name, chain := range registry.entries {
+		inflight[name] = chain.inflight.Load()
+	}
+	return inflight
+}
+
+// InFlightTotal returns the number of active invocations across all functions,
+// including functions that are not registered.
+func (registry *Registry) InFlightTotal() int64 {
+	return registry.active.Load()
+}
+
+// Shutdown rejects new calls with ErrShutdown, waits for active calls to finish,
+// then closes every advice Closer and applied Aspect once. If ctx expires first,
+// ctx.Err() is returned and nothing is closed, since calls may still be using it.
+func (registry *Registry) Shutdown(ctx context.Context) error {
+	registry.closing.Store(true)
+
+	for registry.active.Load() != 0 {
+		select {
+		case <-registry.idle:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	var err error
+	registry.closeOnce.Do(func() {
+		err = registry.closeAdvice()
+	})
+	return err
+}
+
+// Hold counts background work started by advice for the call (e.g. a refresh outliving it) as an
+// in-flight call of the registry that ran it, so Shutdown waits for release before closing aspects.
+// Returns ErrShutdown once the registry is shutting down; contexts not run by a registry get a
+// no-op release. Calling release more than once has no further effect.
+func (aopCtx *Context) Hold() (release func(), err error) {
+	registry := aopCtx.registry
+	if registry == nil {
+		return func() {}, nil
+	}
+	if err := registry.enter(); err != nil {
+		return nil, err
+	}
+
+	var once sync.Once
+	return func() { once.Do(registry.leave) }, nil
+}
+
+// IsShuttingDown returns true once Shutdown has been called.
+func (registry *Registry) IsShuttingDown() bool {
+	return registry.closing.Load()
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// enter admits a new invocation or rejects it with ErrShutdown.
+func (registry *Registry) enter() error {
+	registry.active.Add(1)
+	if registry.closing.Load() {
+		registry.leave()
+		return ErrShutdown
+	}
+	return nil
+}
+
+// leave finishes an invocation and wakes Shutdown when the last one completes.
+func (registry *Registry) leave() {
+	if registry.active.Add(-1) == 0 && registry.closing.Load() {
+		select {
+		case registry.idle <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// closeAdvice closes every distinct advice Closer and aspect, joining their errors.
+func (registry *Registry) closeAdvice() error {
+	registry.mu.RLock()
+	var closers []io.Closer
+	for _, chain := range registry.entries {
+		compiled := chain.compile()
+		for _, adviceList := range [][]Advice{compiled.before, compiled.after, compiled.around, compiled.afterReturning, compiled.afterThrowing} {
+			for _, advice := range adviceList {
+				if advice.Closer != nil && !containsCloser(closers, advice.Closer) {
+					closers = append(closers, advice.Closer)
+				}
+			}
+		}
+	}
+	registry.mu.RUnlock()
+
+	for _, aspect := range registry.Aspects() {
+		if !containsCloser(closers, aspect) {
+			closers = append(closers, aspect)
+		}
+	}
+
+	var errs []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// containsCloser reports whether the same closer instance is already in the list.
+func containsCloser(closers []io.Closer, closer io.Closer) bool {
+	for _, existing := range closers {
+		if sameInstance(existing, closer) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameInstance reports whether two values are the same instance. Pointers, maps, channels and
+// slices compare by address so non-comparable aspects are still recognized; other comparable
+// values compare with ==, and non-comparable values never match.
+func sameInstance(first, second any) bool {
+	firstType, secondType := reflect.TypeOf(first), reflect.TypeOf(second)
+	if firstType != secondType {
+		return false
+	}
+
+	switch firstType.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return reflect.ValueOf(first).Pointer() == reflect.ValueOf(second).Pointer()
+	case reflect.Slice:
+		firstValue, secondValue := reflect.ValueOf(first), reflect.ValueOf(second)
+		return firstValue.Pointer() == secondValue.Pointer() && firstValue.Len() == secondValue.Len()
+	}
+	return firstType.Comparable() && first == second
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// InFlight returns active invocations per function in the global registry.
+func InFlight() map[string]int64 {
+	return globalRegistry.InFlight()
+}
+
+// Shutdown gracefully shuts down the global registry.
+func Shutdown(ctx context.Context) error {
+	return globalRegistry.Shutdown(ctx)
+}
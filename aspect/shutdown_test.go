@@ -0,0 +1,246 @@
+// Package aspect - shutdown_test validates in-flight tracking and graceful shutdown
+package aspect
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// countingCloser counts Close calls.
+type countingCloser struct {
+	closed int
+}
+
+func (closer *countingCloser) Close() error {
+	closer.closed++
+	return nil
+}
+
+// useRegistry installs a fresh global registry for the duration of a test.
+func useRegistry(t *testing.T) *Registry {
+	t.Helper()
+	original := GetGlobalRegistry()
+	registry := NewRegistry()
+	SetGlobalRegistry(registry)
+	t.Cleanup(func() { SetGlobalRegistry(original) })
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestShutdown_WaitsForInFlightCalls(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("SlowCall")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	slowCall := Wrap0RE("SlowCall", func() (string, error) {
+		close(started)
+		<-release
+		return "done", nil
+	})
+
+	finished := make(chan string)
+	go func() {
+		result, _ := slowCall()
+		finished <- result
+	}()
+	<-started
+
+	if inflight := registry.InFlight()["SlowCall"]; inflight != 1 {
+		t.Fatalf("expected 1 in-flight call, got %d", inflight)
+	}
+
+	shutdownDone := make(chan error)
+	go func() { shutdownDone <- registry.Shutdown(context.Background()) }()
+
+	select {
+	case <-shutdownDone:
+		t.Fatal("shutdown returned while a call was in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if result := <-finished; result != "done" {
+		t.Fatalf("expected in-flight call to complete, got %q", result)
+	}
+	if err := <-shutdownDone; err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if registry.InFlightTotal() != 0 {
+		t.Fatalf("expected no in-flight calls, got %d", registry.InFlightTotal())
+	}
+}
+
+func TestShutdown_RejectsNewCalls(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Rejected")
+
+	var targetCalled bool
+	wrapped := Wrap1E("Rejected", func(x int) error {
+		targetCalled = true
+		return nil
+	})
+
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	if err := wrapped(1); !errors.Is(err, ErrShutdown) {
+		t.Fatalf("expected ErrShutdown, got %v", err)
+	}
+	if targetCalled {
+		t.Fatal("target must not run after shutdown")
+	}
+}
+
+func TestShutdown_PanicsWithoutErrorReturn(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Dropped")
+	wrapped := Wrap1("Dropped", func(x int) {})
+
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrShutdown) {
+			t.Fatalf("expected panic with ErrShutdown, got %v", err)
+		}
+	}()
+	wrapped(1)
+}
+
+func TestShutdown_WaitsForHeldWork(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Refresh")
+
+	var release func()
+	registry.MustAddAdvice("Refresh", Advice{Type: Before, Handler: func(ctx *Context) error {
+		var err error
+		release, err = ctx.Hold()
+		return err
+	}})
+	Wrap0("Refresh", func() {})()
+
+	if registry.InFlightTotal() != 1 {
+		t.Fatalf("expected held work to be in flight, got %d", registry.InFlightTotal())
+	}
+	expired, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := registry.Shutdown(expired); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected Shutdown to wait for held work, got %v", err)
+	}
+
+	release()
+	release()
+	if err := registry.Shutdown(context.Background()); err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+	if _, err := NewContext("Refresh").Hold(); err != nil {
+		t.Fatalf("expected a no-op hold outside a registry, got %v", err)
+	}
+}
+
+func TestShutdown_ContextExpires(t *testing.T) {
+	registry := useRegistry(t)
+	closer := &countingCloser{}
+	registry.MustRegister("Stuck")
+	registry.MustAddAdvice("Stuck", Advice{Type: Before, Handler: func(ctx *Context) error { return nil }, Closer: closer})
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	stuck := Wrap0("Stuck", func() {
+		close(started)
+		<-release
+	})
+	go stuck()
+	<-started
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := registry.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if closer.closed != 0 {
+		t.Fatal("advice must not be closed while calls are in flight")
+	}
+}
+
+func TestShutdown_ClosesAdviceOnce(t *testing.T) {
+	registry := useRegistry(t)
+	shared := &countingCloser{}
+	for _, name := range []string{"Func1", "Func2"} {
+		registry.MustRegister(name)
+		registry.MustAddAdvice(name, Advice{Type: Before, Handler: func(ctx *Context) error { return nil }, Closer: shared})
+	}
+
+	_ = registry.Shutdown(context.Background())
+	_ = registry.Shutdown(context.Background())
+
+	if shared.closed != 1 {
+		t.Fatalf("expected shared closer to be closed once, got %d", shared.closed)
+	}
+}
+
+func TestWrap_SurfacesContextError(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Guarded")
+
+	errBlocked := errors.New("blocked")
+	registry.MustAddAdvice("Guarded", Advice{
+		Type:     Around,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			ctx.Error = errBlocked
+			ctx.Skipped = true
+			return nil
+		},
+	})
+
+	wrapped := Wrap1RE("Guarded", func(x int) (int, error) { return x, nil })
+	if _, err := wrapped(1); !errors.Is(err, errBlocked) {
+		t.Fatalf("expected error set by Around advice, got %v", err)
+	}
+
+	// Without an error return, the rejection panics rather than returning a zero value
+	wrappedWithoutError := Wrap1R("Guarded", func(x int) int { return x })
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, errBlocked) {
+			t.Fatalf("expected panic with the error set by Around advice, got %v", err)
+		}
+	}()
+	wrappedWithoutError(1)
+}
+
+func TestWrap_ReportsResultTypeMismatch(t *testing.T) {
+	registry := useRegistry(t)
+	registry.MustRegister("Mistyped")
+	registry.MustAddAdvice("Mistyped", Advice{
+		Type: Around,
+		Handler: func(ctx *Context) error {
+			ctx.SetResult(0, 5)
+			ctx.Skipped = true
+			return nil
+		},
+	})
+
+	wrappedWithError := Wrap0RE("Mistyped", func() (int64, error) { return 1, nil })
+	if result, err := wrappedWithError(); !errors.Is(err, ErrTypeMismatch) || result != 0 {
+		t.Fatalf("expected ErrTypeMismatch, got (%v, %v)", result, err)
+	}
+
+	wrapped := Wrap0R("Mistyped", func() int64 { return 1 })
+	defer func() {
+		if err, _ := recover().(error); !errors.Is(err, ErrTypeMismatch) {
+			t.Fatalf("expected panic with ErrTypeMismatch, got %v", err)
+		}
+	}()
+	wrapped()
+}
@@ -0,0 +1,217 @@
+// Package singleflight - singleflight provides an aspect coalescing concurrent identical calls
+// into a single execution whose result and error are shared by every caller
+package singleflight
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/cache"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// SharedKey is the aspect.Context metadata key set to true on calls that received another call's result.
+const SharedKey = "singleflight.shared"
+
+const defaultName = "singleflight"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Config configures a Group. Zero values fall back to defaults.
+type Config struct {
+	Name     string // Name of the advice, for runtime switches (default "singleflight").
+	Priority int    // Priority of the Around advice.
+
+	// KeyFunc derives the key identifying identical calls of a function
+	// (default cache.Args(), every argument except a context.Context).
+	KeyFunc cache.KeyFunc
+}
+
+// Group is an aspect deduplicating in-flight calls of the functions it is applied to.
+// Results are shared, not copied: callers must not mutate returned pointers, maps or slices.
+type Group struct {
+	config Config
+
+	mu       sync.Mutex
+	calls    map[string]*call
+	counters map[string]*counters
+}
+
+// counters count the calls of a function that waited for another call.
+type counters struct {
+	coalesced atomic.Uint64 // coalesced counts waiters that received the leader's result.
+	cancelled atomic.Uint64 // cancelled counts waiters whose own context ended first.
+	waiting   atomic.Int64  // waiting counts the calls currently waiting.
+}
+
+// call is an in-flight execution and its outcome.
+type call struct {
+	done       chan struct{}
+	results    []any
+	err        error
+	panicValue any
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Group, filling unset configuration with defaults.
+func New(config Config) *Group {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = cache.Args()
+	}
+	return &Group{
+		config:   config,
+		calls:    make(map[string]*call),
+		counters: make(map[string]*counters),
+	}
+}
+
+// Name returns the advice name.
+func (group *Group) Name() string {
+	return group.config.Name
+}
+
+// Advice returns the Around advice coalescing calls.
+func (group *Group) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     group.config.Name,
+			Type:     aspect.Around,
+			Priority: group.config.Priority,
+			Handler:  group.around,
+		},
+	}
+}
+
+// Init implements aspect.Aspect; calls are tracked lazily.
+func (group *Group) Init() error {
+	return nil
+}
+
+// Close implements aspect.Aspect; in-flight calls complete normally.
+func (group *Group) Close() error {
+	return nil
+}
+
+// Coalesced returns the number of calls of a function that shared another call's result.
+// Waiters that gave up because their own context ended are counted by Cancelled instead.
+func (group *Group) Coalesced(functionName string) uint64 {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if counters, exists := group.counters[functionName]; exists {
+		return counters.coalesced.Load()
+	}
+	return 0
+}
+
+// Waiting returns the number of calls of a function currently waiting for another call's result.
+func (group *Group) Waiting(functionName string) int64 {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if counters, exists := group.counters[functionName]; exists {
+		return counters.waiting.Load()
+	}
+	return 0
+}
+
+// Cancelled returns the number of calls of a function that stopped waiting for another call's
+// result because their own context was done.
+func (group *Group) Cancelled(functionName string) uint64 {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if counters, exists := group.counters[functionName]; exists {
+		return counters.cancelled.Load()
+	}
+	return 0
+}
+
+// Shared returns true if the call received the result of another in-flight call.
+func Shared(ctx *aspect.Context) bool {
+	shared, _ := ctx.Metadata[SharedKey].(bool)
+	return shared
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around joins an identical in-flight call or leads a new one.
+func (group *Group) around(ctx *aspect.Context) error {
+	key := ctx.FunctionName + ":" + group.config.KeyFunc(ctx)
+
+	group.mu.Lock()
+	if inflight, exists := group.calls[key]; exists {
+		group.mu.Unlock()
+		group.wait(ctx, inflight)
+		return nil
+	}
+	leader := &call{done: make(chan struct{})}
+	group.calls[key] = leader
+	group.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			leader.panicValue = r
+			group.finish(key, leader)
+			panic(r)
+		}
+	}()
+
+	_ = ctx.Proceed()
+	leader.results = append([]any(nil), ctx.Results...)
+	leader.err = ctx.Error
+	group.finish(key, leader)
+	return nil
+}
+
+// wait blocks until the leader finishes or the caller's context is done, then shares the outcome.
+func (group *Group) wait(ctx *aspect.Context, inflight *call) {
+	ctx.Skipped = true
+	counters := group.countersOf(ctx.FunctionName)
+	counters.waiting.Add(1)
+	defer counters.waiting.Add(-1)
+
+	select {
+	case <-inflight.done:
+		counters.coalesced.Add(1)
+	case <-ctx.Context().Done():
+		counters.cancelled.Add(1)
+		ctx.Error = context.Cause(ctx.Context())
+		return
+	}
+
+	if inflight.panicValue != nil {
+		panic(inflight.panicValue)
+	}
+	ctx.Results = append([]any(nil), inflight.results...)
+	ctx.Error = inflight.err
+	ctx.Metadata[SharedKey] = true
+}
+
+// finish publishes the leader's outcome and lets new calls start a fresh execution.
+func (group *Group) finish(key string, leader *call) {
+	group.mu.Lock()
+	delete(group.calls, key)
+	group.mu.Unlock()
+	close(leader.done)
+}
+
+// countersOf returns the counters of a function, creating them on first use.
+func (group *Group) countersOf(functionName string) *counters {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	existing, exists := group.counters[functionName]
+	if !exists {
+		existing = &counters{}
+		group.counters[functionName] = existing
+	}
+	return existing
+}
@@ -0,0 +1,161 @@
+// Package singleflight - singleflight_test validates call coalescing and outcome sharing
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// waitFor polls condition until it holds or the test times out.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// setup registers functions on a fresh global registry and applies the group to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestGroup_CoalescesIdenticalCalls(t *testing.T) {
+	group := New(Config{})
+	setup(t, group, "LoadReport")
+
+	release := make(chan struct{})
+	var executions atomic.Int32
+	loadReport := aspect.Wrap1RE("LoadReport", func(id string) (string, error) {
+		executions.Add(1)
+		<-release
+		return "report " + id, nil
+	})
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]string, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = loadReport("q1")
+		}(i)
+	}
+
+	waitFor(t, func() bool { return group.Waiting("LoadReport") == callers-1 })
+	close(release)
+	wg.Wait()
+
+	if executions.Load() != 1 || group.Coalesced("LoadReport") != callers-1 {
+		t.Fatalf("expected a single execution shared by %d callers, got %d executions and %d coalesced",
+			callers-1, executions.Load(), group.Coalesced("LoadReport"))
+	}
+	for i, result := range results {
+		if result != "report q1" {
+			t.Errorf("caller %d: expected shared result, got %q", i, result)
+		}
+	}
+
+	// Once finished, the next call executes again
+	_, _ = loadReport("q1")
+	if executions.Load() != 2 {
+		t.Fatalf("expected a new execution after completion, got %d", executions.Load())
+	}
+}
+
+func TestGroup_SharesErrorsAndKeepsKeysApart(t *testing.T) {
+	group := New(Config{})
+	registry := setup(t, group, "Fetch")
+
+	var shared atomic.Int32
+	registry.MustAddAdvice("Fetch", aspect.Advice{
+		Type: aspect.After,
+		Handler: func(ctx *aspect.Context) error {
+			if Shared(ctx) {
+				shared.Add(1)
+			}
+			return nil
+		},
+	})
+
+	errBackend := errors.New("backend down")
+	release := make(chan struct{})
+	var executions atomic.Int32
+	fetch := aspect.Wrap1RE("Fetch", func(id int) (int, error) {
+		executions.Add(1)
+		<-release
+		return 0, errBackend
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	for i, id := range []int{1, 1, 2} {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			_, errs[i] = fetch(id)
+		}(i, id)
+	}
+
+	waitFor(t, func() bool { return group.Waiting("Fetch") == 1 && executions.Load() == 2 })
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if !errors.Is(err, errBackend) {
+			t.Errorf("caller %d: expected shared error, got %v", i, err)
+		}
+	}
+	if shared.Load() != 1 {
+		t.Fatalf("expected one shared call, got %d", shared.Load())
+	}
+}
+
+func TestGroup_WaiterHonorsContext(t *testing.T) {
+	group := New(Config{})
+	setup(t, group, "Slow")
+
+	release := make(chan struct{})
+	defer close(release)
+	slow := aspect.Wrap2RE("Slow", func(ctx context.Context, id int) (int, error) {
+		<-release
+		return id, nil
+	})
+
+	go func() { _, _ = slow(context.Background(), 1) }()
+	waitFor(t, func() bool { return aspect.InFlight()["Slow"] == 1 })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := slow(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected waiter to stop on cancellation, got %v", err)
+	}
+	if group.Cancelled("Slow") != 1 || group.Coalesced("Slow") != 0 || group.Waiting("Slow") != 0 {
+		t.Fatalf("expected the waiter counted as cancelled only, got %d cancelled and %d coalesced",
+			group.Cancelled("Slow"), group.Coalesced("Slow"))
+	}
+}
@@ -0,0 +1,245 @@
+// Package timeout - timeout provides an aspect bounding a wrapped function's execution time,
+// cancelling context-accepting targets and optionally abandoning non-cooperative ones
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const defaultName = "timeout"
+
+// Abandoned call states, see Timeout.abandon.
+const (
+	running int32 = iota
+	finished
+	abandoned
+)
+
+// ErrTimeout is matched (via errors.Is) by errors returned for calls exceeding their timeout.
+var ErrTimeout = errors.New("call timed out")
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Error is the error set on calls exceeding their timeout. It also matches context.DeadlineExceeded.
+type Error struct {
+	Name     string        // Name is the timeout aspect name.
+	Function string        // Function is the function that timed out.
+	Timeout  time.Duration // Timeout is the exceeded limit.
+}
+
+// Stats are the timeout counters of a single function.
+type Stats struct {
+	Timeouts  uint64 // Timeouts are calls that exceeded the timeout.
+	Abandoned uint64 // Abandoned are timed out calls whose target was left running (Config.Abandon).
+	Running   int64  // Running are abandoned targets that have not finished yet; a growing value is a leak.
+	Late      uint64 // Late are abandoned targets that finished after the timeout.
+}
+
+// Config configures a Timeout. Zero values fall back to defaults.
+type Config struct {
+	Name     string        // Name of the advice, for runtime switches (default "timeout").
+	Priority int           // Priority of the Around advice.
+	Timeout  time.Duration // Timeout bounds each call (required).
+
+	// Abandon returns ErrTimeout as soon as the timeout elapses, leaving the target running in its
+	// goroutine; Registry.Shutdown still waits for it. Without it, targets that ignore their
+	// context.Context are waited for and the timeout is only reported once they return; their
+	// results are then discarded.
+	Abandon bool
+	// OnLate is called when an abandoned target finishes, with its total duration and error (optional).
+	OnLate func(functionName string, elapsed time.Duration, err error)
+}
+
+// Timeout is an aspect bounding the execution time of the functions it is applied to.
+// Context-accepting targets receive a context.Context carrying the deadline as first argument.
+type Timeout struct {
+	config Config
+
+	mu    sync.Mutex
+	stats map[string]*counters
+}
+
+// counters are the live Stats of a function.
+type counters struct {
+	timeouts, abandoned, late atomic.Uint64
+	running                   atomic.Int64
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Timeout, filling unset configuration with defaults.
+func New(config Config) *Timeout {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	return &Timeout{config: config, stats: make(map[string]*counters)}
+}
+
+// Name returns the advice name.
+func (timeout *Timeout) Name() string {
+	return timeout.config.Name
+}
+
+// Advice returns the Around advice enforcing the timeout.
+func (timeout *Timeout) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     timeout.config.Name,
+			Type:     aspect.Around,
+			Priority: timeout.config.Priority,
+			Handler:  timeout.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (timeout *Timeout) Init() error {
+	if timeout.config.Timeout <= 0 {
+		return fmt.Errorf("timeout '%s': timeout must be positive", timeout.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; abandoned targets keep running until they return.
+func (timeout *Timeout) Close() error {
+	return nil
+}
+
+// Stats returns the counters of a function.
+func (timeout *Timeout) Stats(functionName string) Stats {
+	counters := timeout.counters(functionName)
+	return Stats{
+		Timeouts:  counters.timeouts.Load(),
+		Abandoned: counters.abandoned.Load(),
+		Running:   counters.running.Load(),
+		Late:      counters.late.Load(),
+	}
+}
+
+// Error implements the error interface.
+func (err *Error) Error() string {
+	return fmt.Sprintf("timeout '%s': call to '%s' timed out after %v", err.Name, err.Function, err.Timeout)
+}
+
+// Unwrap makes Error match ErrTimeout and context.DeadlineExceeded.
+func (err *Error) Unwrap() []error {
+	return []error{ErrTimeout, context.DeadlineExceeded}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around runs the call under a deadline, replacing a context.Context first argument.
+func (timeout *Timeout) around(ctx *aspect.Context) error {
+	timeoutErr := &Error{Name: timeout.config.Name, Function: ctx.FunctionName, Timeout: timeout.config.Timeout}
+	parent := ctx.Context()
+	deadline, cancel := context.WithTimeoutCause(parent, timeout.config.Timeout, timeoutErr)
+
+	if len(ctx.Args) > 0 {
+		if _, acceptsContext := ctx.Args[0].(context.Context); acceptsContext {
+			ctx.Args[0] = deadline
+			defer func() { ctx.Args[0] = parent }()
+		}
+	}
+
+	if timeout.config.Abandon {
+		// An abandoned target counts as in flight until it returns, so Shutdown waits for it; once
+		// shutting down, the call is waited for instead
+		if release, err := ctx.Hold(); err == nil {
+			timeout.abandon(ctx, deadline, cancel, timeoutErr, release)
+			return nil
+		}
+	}
+
+	defer cancel()
+	_ = ctx.Proceed()
+	if context.Cause(deadline) == error(timeoutErr) {
+		// A target finishing past the deadline fails as a whole, even if it ignored its context
+		// and returned results: the caller gets zero values and the timeout, never a mix of both
+		timeout.counters(ctx.FunctionName).timeouts.Add(1)
+		ctx.Results = make([]any, 0)
+		ctx.Error = timeoutErr
+	}
+	return nil
+}
+
+// abandon runs the rest of the chain in a goroutine and stops waiting when the deadline or
+// the caller's context ends, leaving the target to finish on its own. release is called once
+// the goroutine returns.
+func (timeout *Timeout) abandon(ctx *aspect.Context, deadline context.Context, cancel context.CancelFunc, timeoutErr *Error, release func()) {
+	counters := timeout.counters(ctx.FunctionName)
+	fork := ctx.Fork()
+	start := time.Now()
+
+	var state atomic.Int32
+	done := make(chan any, 1)
+	go func() {
+		defer release()
+		defer cancel()
+		defer func() {
+			panicValue := recover()
+			if state.CompareAndSwap(running, finished) {
+				done <- panicValue
+				return
+			}
+
+			// Nobody is waiting any more: record the late completion (a late panic is dropped)
+			counters.running.Add(-1)
+			counters.late.Add(1)
+			if timeout.config.OnLate != nil {
+				timeout.config.OnLate(fork.FunctionName, time.Since(start), fork.Error)
+			}
+		}()
+		_ = fork.Proceed()
+	}()
+
+	var panicValue any
+	select {
+	case <-deadline.Done():
+		counters.running.Add(1) // Counted first so the goroutine never sees it below zero
+		if state.CompareAndSwap(running, abandoned) {
+			counters.abandoned.Add(1)
+			ctx.Error = context.Cause(deadline)
+			if ctx.Error == error(timeoutErr) {
+				counters.timeouts.Add(1)
+			}
+			ctx.Skipped = true
+			return
+		}
+		// The target finished right at the deadline; its outcome is on the way
+		counters.running.Add(-1)
+		panicValue = <-done
+	case panicValue = <-done:
+	}
+
+	if panicValue != nil {
+		panic(panicValue)
+	}
+	ctx.Results = fork.Results
+	ctx.Error = fork.Error
+	ctx.Skipped = fork.Skipped
+	for key, value := range fork.Metadata {
+		ctx.Metadata[key] = value
+	}
+}
+
+// counters returns the counters of a function, creating them on first use.
+func (timeout *Timeout) counters(functionName string) *counters {
+	timeout.mu.Lock()
+	defer timeout.mu.Unlock()
+
+	functionCounters, exists := timeout.stats[functionName]
+	if !exists {
+		functionCounters = &counters{}
+		timeout.stats[functionName] = functionCounters
+	}
+	return functionCounters
+}
@@ -0,0 +1,180 @@
+// Package timeout - timeout_test validates deadlines, cancellation and abandoned targets
+package timeout
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies the timeout to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestTimeout_CancelsContextAwareTarget(t *testing.T) {
+	timeout := New(Config{Timeout: 20 * time.Millisecond})
+	setup(t, timeout, "Query")
+
+	var sawDeadline bool
+	query := aspect.Wrap2RE("Query", func(ctx context.Context, sql string) (int, error) {
+		_, sawDeadline = ctx.Deadline()
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+
+	_, err := query(context.Background(), "SELECT 1")
+	var timeoutErr *Error
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) || !errors.As(err, &timeoutErr) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if !sawDeadline || timeoutErr.Function != "Query" {
+		t.Fatalf("expected target to receive a deadline, got deadline=%v function=%q", sawDeadline, timeoutErr.Function)
+	}
+	if stats := timeout.Stats("Query"); stats.Timeouts != 1 || stats.Abandoned != 0 {
+		t.Fatalf("expected 1 timeout without abandonment, got %+v", stats)
+	}
+}
+
+func TestTimeout_FastCallsUnaffected(t *testing.T) {
+	setup(t, New(Config{Timeout: time.Second}), "Fast")
+
+	fast := aspect.Wrap1RE("Fast", func(ctx context.Context) (string, error) { return "ok", nil })
+	if result, err := fast(context.Background()); err != nil || result != "ok" {
+		t.Fatalf("expected (ok, nil), got (%q, %v)", result, err)
+	}
+}
+
+func TestTimeout_ParentCancellationIsNotATimeout(t *testing.T) {
+	timeout := New(Config{Timeout: time.Second})
+	setup(t, timeout, "Cancelled")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := aspect.Wrap1E("Cancelled", func(ctx context.Context) error {
+		cancel()
+		return ctx.Err()
+	})
+
+	if err := cancelled(ctx); !errors.Is(err, context.Canceled) || errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected caller cancellation, got %v", err)
+	}
+	if timeout.Stats("Cancelled").Timeouts != 0 {
+		t.Fatal("expected no timeout recorded")
+	}
+}
+
+func TestTimeout_LateResultsAreDiscarded(t *testing.T) {
+	setup(t, New(Config{Timeout: 10 * time.Millisecond}), "Render")
+
+	render := aspect.Wrap1RE("Render", func(page string) (string, error) {
+		time.Sleep(30 * time.Millisecond) // Ignores any deadline
+		return "<html>" + page, nil
+	})
+
+	if result, err := render("home"); !errors.Is(err, ErrTimeout) || result != "" {
+		t.Fatalf("expected a timeout without results, got (%q, %v)", result, err)
+	}
+}
+
+func TestTimeout_AbandonsNonCooperativeTarget(t *testing.T) {
+	late := make(chan time.Duration, 1)
+	timeout := New(Config{
+		Timeout: 10 * time.Millisecond,
+		Abandon: true,
+		OnLate:  func(functionName string, elapsed time.Duration, err error) { late <- elapsed },
+	})
+	setup(t, timeout, "Stuck")
+
+	release := make(chan struct{})
+	stuck := aspect.Wrap0RE("Stuck", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	start := time.Now()
+	if _, err := stuck(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected caller to return at the deadline, took %v", elapsed)
+	}
+	if stats := timeout.Stats("Stuck"); stats.Abandoned != 1 || stats.Running != 1 {
+		t.Fatalf("expected 1 abandoned running target, got %+v", stats)
+	}
+
+	close(release)
+	<-late
+	if stats := timeout.Stats("Stuck"); stats.Running != 0 || stats.Late != 1 {
+		t.Fatalf("expected late completion to be recorded, got %+v", stats)
+	}
+}
+
+func TestTimeout_ShutdownWaitsForAbandonedTarget(t *testing.T) {
+	registry := setup(t, New(Config{Timeout: 10 * time.Millisecond, Abandon: true}), "Stuck")
+
+	release := make(chan struct{})
+	stuck := aspect.Wrap0RE("Stuck", func() (int, error) {
+		<-release
+		return 1, nil
+	})
+	if _, err := stuck(); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	shutdown := make(chan error)
+	go func() { shutdown <- registry.Shutdown(context.Background()) }()
+	select {
+	case <-shutdown:
+		t.Fatal("expected Shutdown to wait for the abandoned target")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-shutdown; err != nil {
+		t.Fatalf("unexpected shutdown error: %v", err)
+	}
+}
+
+func TestTimeout_AbandonModeReturnsResults(t *testing.T) {
+	setup(t, New(Config{Timeout: time.Second, Abandon: true}), "Quick")
+
+	calls := 0
+	quick := aspect.Wrap1RE("Quick", func(x int) (int, error) {
+		calls++
+		return x * 2, nil
+	})
+	if result, err := quick(21); err != nil || result != 42 || calls != 1 {
+		t.Fatalf("expected (42, nil) from a single call, got (%d, %v) after %d calls", result, err, calls)
+	}
+
+	panicky := aspect.Wrap0("Quick", func() { panic("boom") })
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatalf("expected panic to propagate to the caller, got %v", r)
+		}
+	}()
+	panicky()
+}
+
+func TestTimeout_InitRequiresTimeout(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Fatal("expected error without timeout")
+	}
+}
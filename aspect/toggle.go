@@ -0,0 +1,221 @@
+// Package aspect - toggle provides runtime switches to enable/disable advice and whole functions
+package aspect
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const (
+	AdviceState   StateKind = iota // AdviceState reports an advice switched on/off by name.
+	FunctionState                  // FunctionState reports all advice of a function switched on/off.
+	GlobalState                    // GlobalState reports the registry-wide kill switch.
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// StateKind identifies which switch a StateChange refers to.
+type StateKind int
+
+// StateChange describes a runtime switch that was flipped.
+type StateChange struct {
+	Kind    StateKind // Kind tells which switch changed.
+	Name    string    // Name is the advice or function name (empty for GlobalState).
+	Enabled bool      // Enabled is the new state of the switch.
+}
+
+// switchboard holds the runtime switches of a registry.
+// Readers only perform atomic loads; writers are serialized by mu.
+type switchboard struct {
+	killed   atomic.Bool
+	disabled atomic.Pointer[map[string]struct{}]
+
+	mu       sync.Mutex
+	watchers broadcaster[StateChange]
+}
+
+// newSwitchboard creates a switchboard with everything enabled.
+func newSwitchboard() *switchboard {
+	return &switchboard{}
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// DisableAdvice switches off every advice with the given name across all functions.
+// The advice stays attached and can be switched back on with EnableAdvice.
+func (registry *Registry) DisableAdvice(name string) error {
+	if name == "" {
+		return fmt.Errorf("advice name cannot be empty")
+	}
+	registry.switches.setAdvice(name, false)
+	return nil
+}
+
+// EnableAdvice switches an advice previously disabled with DisableAdvice back on.
+func (registry *Registry) EnableAdvice(name string) error {
+	if name == "" {
+		return fmt.Errorf("advice name cannot be empty")
+	}
+	registry.switches.setAdvice(name, true)
+	return nil
+}
+
+// IsAdviceEnabled returns false if the advice name was switched off with DisableAdvice.
+func (registry *Registry) IsAdviceEnabled(name string) bool {
+	_, off := registry.switches.disabledAdvice()[name]
+	return !off
+}
+
+// DisableFunction bypasses all advice of a function; the target still runs.
+// Returns error if the function is not registered.
+func (registry *Registry) DisableFunction(name string) error {
+	return registry.setFunction(name, false)
+}
+
+// EnableFunction re-enables advice of a function disabled with DisableFunction.
+// Returns error if the function is not registered.
+func (registry *Registry) EnableFunction(name string) error {
+	return registry.setFunction(name, true)
+}
+
+// IsFunctionEnabled returns false if the function was disabled with DisableFunction.
+// Returns false if the function is not registered.
+func (registry *Registry) IsFunctionEnabled(name string) bool {
+	chain, err := registry.GetAdviceChain(name)
+	if err != nil {
+		return false
+	}
+	return !chain.disabled.Load()
+}
+
+// DisableAll is the global kill switch: every wrapped function runs without advice.
+func (registry *Registry) DisableAll() {
+	registry.switches.setGlobal(false)
+}
+
+// EnableAll releases the global kill switch.
+func (registry *Registry) EnableAll() {
+	registry.switches.setGlobal(true)
+}
+
+// IsEnabled returns false while the global kill switch is engaged.
+func (registry *Registry) IsEnabled() bool {
+	return !registry.switches.killed.Load()
+}
+
+// OnStateChange registers a callback invoked after any runtime switch changes state.
+// Callbacks run synchronously on the goroutine that flipped the switch.
+// Returns a function that removes the callback.
+func (registry *Registry) OnStateChange(callback func(StateChange)) (unsubscribe func()) {
+	return registry.switches.watchers.subscribe(callback)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// setFunction flips the per-function switch and notifies watchers on change.
+func (registry *Registry) setFunction(name string, enabled bool) error {
+	chain, err := registry.GetAdviceChain(name)
+	if err != nil {
+		return err
+	}
+
+	registry.switches.mu.Lock()
+	changed := chain.disabled.Swap(!enabled) == enabled
+	registry.switches.mu.Unlock()
+
+	if changed {
+		registry.switches.watchers.publish(StateChange{Kind: FunctionState, Name: name, Enabled: enabled})
+	}
+	return nil
+}
+
+// disabledAdvice returns the current set of disabled advice names (nil when none).
+func (switches *switchboard) disabledAdvice() map[string]struct{} {
+	if switches == nil {
+		return nil
+	}
+	if set := switches.disabled.Load(); set != nil {
+		return *set
+	}
+	return nil
+}
+
+// setAdvice replaces the disabled set copy-on-write so readers never lock.
+func (switches *switchboard) setAdvice(name string, enabled bool) {
+	switches.mu.Lock()
+	current := switches.disabledAdvice()
+	if _, off := current[name]; off != enabled {
+		switches.mu.Unlock()
+		return
+	}
+
+	next := make(map[string]struct{}, len(current)+1)
+	for adviceName := range current {
+		next[adviceName] = struct{}{}
+	}
+	if enabled {
+		delete(next, name)
+	} else {
+		next[name] = struct{}{}
+	}
+
+	if len(next) == 0 {
+		switches.disabled.Store(nil)
+	} else {
+		switches.disabled.Store(&next)
+	}
+	switches.mu.Unlock()
+
+	switches.watchers.publish(StateChange{Kind: AdviceState, Name: name, Enabled: enabled})
+}
+
+// setGlobal flips the kill switch and notifies watchers on change.
+func (switches *switchboard) setGlobal(enabled bool) {
+	switches.mu.Lock()
+	changed := switches.killed.Swap(!enabled) == enabled
+	switches.mu.Unlock()
+
+	if changed {
+		switches.watchers.publish(StateChange{Kind: GlobalState, Enabled: enabled})
+	}
+}
+
+// -------------------------------------------- Global Registry Functions --------------------------------------------
+
+// DisableAdvice switches off an advice by name in the global registry.
+func DisableAdvice(name string) error {
+	return globalRegistry.DisableAdvice(name)
+}
+
+// EnableAdvice switches an advice back on in the global registry.
+func EnableAdvice(name string) error {
+	return globalRegistry.EnableAdvice(name)
+}
+
+// DisableFunction bypasses all advice of a function in the global registry.
+func DisableFunction(name string) error {
+	return globalRegistry.DisableFunction(name)
+}
+
+// EnableFunction re-enables advice of a function in the global registry.
+func EnableFunction(name string) error {
+	return globalRegistry.EnableFunction(name)
+}
+
+// DisableAll engages the kill switch of the global registry.
+func DisableAll() {
+	globalRegistry.DisableAll()
+}
+
+// EnableAll releases the kill switch of the global registry.
+func EnableAll() {
+	globalRegistry.EnableAll()
+}
+
+// OnStateChange subscribes to runtime switch changes of the global registry.
+func OnStateChange(callback func(StateChange)) (unsubscribe func()) {
+	return globalRegistry.OnStateChange(callback)
+}
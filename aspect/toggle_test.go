@@ -0,0 +1,162 @@
+// Package aspect - toggle_test validates runtime enable/disable switches
+package aspect
+
+import (
+	"testing"
+)
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestToggle_DisableAdviceByName(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	var verboseCalls, otherCalls int
+	MustRegister("ToggleAdvice")
+	MustAddAdvice("ToggleAdvice", Advice{
+		Name:     "verbose-args",
+		Type:     Before,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			verboseCalls++
+			return nil
+		},
+	})
+	MustAddAdvice("ToggleAdvice", Advice{
+		Type:     Before,
+		Priority: 50,
+		Handler: func(ctx *Context) error {
+			otherCalls++
+			return nil
+		},
+	})
+
+	wrapped := Wrap0("ToggleAdvice", func() {})
+
+	if err := DisableAdvice("verbose-args"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wrapped()
+	if verboseCalls != 0 || otherCalls != 1 {
+		t.Fatalf("expected only unnamed advice to run, got verbose=%d other=%d", verboseCalls, otherCalls)
+	}
+
+	_ = EnableAdvice("verbose-args")
+	wrapped()
+	if verboseCalls != 1 || otherCalls != 2 {
+		t.Fatalf("expected both advice to run, got verbose=%d other=%d", verboseCalls, otherCalls)
+	}
+
+	if err := DisableAdvice(""); err == nil {
+		t.Fatal("expected error for empty advice name")
+	}
+}
+
+func TestToggle_DisableFunction(t *testing.T) {
+	Clear()
+	defer Clear()
+
+	var adviceCalls, targetCalls int
+	MustRegister("ToggleFunction")
+	MustAddAdvice("ToggleFunction", Advice{
+		Type:     Before,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			adviceCalls++
+			return nil
+		},
+	})
+
+	wrapped := Wrap0("ToggleFunction", func() { targetCalls++ })
+
+	if err := DisableFunction("ToggleFunction"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if GetGlobalRegistry().IsFunctionEnabled("ToggleFunction") {
+		t.Fatal("expected function to be disabled")
+	}
+	wrapped()
+	if adviceCalls != 0 || targetCalls != 1 {
+		t.Fatalf("expected target without advice, got advice=%d target=%d", adviceCalls, targetCalls)
+	}
+
+	_ = EnableFunction("ToggleFunction")
+	wrapped()
+	if adviceCalls != 1 || targetCalls != 2 {
+		t.Fatalf("expected target with advice, got advice=%d target=%d", adviceCalls, targetCalls)
+	}
+
+	if err := DisableFunction("NotRegistered"); err == nil {
+		t.Fatal("expected error for unregistered function")
+	}
+}
+
+func TestToggle_GlobalKillSwitch(t *testing.T) {
+	Clear()
+	defer Clear()
+	defer EnableAll()
+
+	var adviceCalls int
+	MustRegister("ToggleGlobal")
+	MustAddAdvice("ToggleGlobal", Advice{
+		Type:     After,
+		Priority: 100,
+		Handler: func(ctx *Context) error {
+			adviceCalls++
+			return nil
+		},
+	})
+
+	wrapped := Wrap1R("ToggleGlobal", func(x int) int { return x + 1 })
+
+	DisableAll()
+	if result := wrapped(1); result != 2 {
+		t.Fatalf("expected 2, got %d", result)
+	}
+	if adviceCalls != 0 {
+		t.Fatalf("expected no advice with kill switch engaged, got %d", adviceCalls)
+	}
+
+	EnableAll()
+	wrapped(1)
+	if adviceCalls != 1 {
+		t.Fatalf("expected advice after release, got %d", adviceCalls)
+	}
+}
+
+func TestToggle_OnStateChange(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("Watched")
+
+	var changes []StateChange
+	unsubscribe := registry.OnStateChange(func(change StateChange) {
+		changes = append(changes, change)
+	})
+
+	_ = registry.DisableAdvice("logging")
+	_ = registry.DisableAdvice("logging") // no-op, already disabled
+	_ = registry.DisableFunction("Watched")
+	registry.DisableAll()
+	registry.EnableAll()
+
+	expected := []StateChange{
+		{Kind: AdviceState, Name: "logging", Enabled: false},
+		{Kind: FunctionState, Name: "Watched", Enabled: false},
+		{Kind: GlobalState, Enabled: false},
+		{Kind: GlobalState, Enabled: true},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %d: %v", len(expected), len(changes), changes)
+	}
+	for i, change := range expected {
+		if changes[i] != change {
+			t.Errorf("change %d: expected %+v, got %+v", i, change, changes[i])
+		}
+	}
+
+	unsubscribe()
+	_ = registry.EnableAdvice("logging")
+	if len(changes) != len(expected) {
+		t.Fatal("expected no notifications after unsubscribe")
+	}
+}
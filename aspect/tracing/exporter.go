@@ -0,0 +1,238 @@
+// Package tracing - exporter provides in-memory, JSON-lines and Chrome trace event exporters
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ErrExporterClosed is returned by ChromeExporter.Export after Close.
+var ErrExporterClosed = errors.New("exporter closed")
+
+// -------------------------------------------- Types --------------------------------------------
+
+// MemoryExporter keeps finished spans in memory, e.g. for tests and debug endpoints.
+type MemoryExporter struct {
+	mu    sync.Mutex
+	spans []*Span
+	limit int
+}
+
+// JSONLinesExporter writes each finished span as one JSON object per line.
+type JSONLinesExporter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// ChromeExporter writes finished spans in the Chrome trace event format, a JSON array loadable
+// in chrome://tracing or Perfetto. Each trace in progress gets its own row, reused once its root
+// span ends; Close terminates the array.
+type ChromeExporter struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	written bool
+	closed  bool
+	rows    map[string]int // rows are the rows of the traces in progress.
+	free    []int          // free are the rows of ended traces, for reuse.
+	next    int            // next is the row after the last one ever used.
+}
+
+// chromeEvent is a single entry of the Chrome trace event format.
+type chromeEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat"`
+	Phase     string         `json:"ph"`
+	Timestamp int64          `json:"ts"`            // Timestamp is in microseconds.
+	Duration  int64          `json:"dur,omitempty"` // Duration is in microseconds (complete events only).
+	Scope     string         `json:"s,omitempty"`   // Scope of instant events.
+	Process   int            `json:"pid"`
+	Thread    int            `json:"tid"`
+	Args      map[string]any `json:"args,omitempty"`
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// NewMemoryExporter creates an exporter keeping the last limit spans (limit <= 0 keeps every span).
+func NewMemoryExporter(limit int) *MemoryExporter {
+	return &MemoryExporter{limit: limit}
+}
+
+// Export implements Exporter.
+func (exporter *MemoryExporter) Export(span *Span) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	exporter.spans = append(exporter.spans, span)
+	if exporter.limit > 0 && len(exporter.spans) > exporter.limit {
+		exporter.spans = exporter.spans[len(exporter.spans)-exporter.limit:]
+	}
+	return nil
+}
+
+// Spans returns the kept spans in the order they finished.
+func (exporter *MemoryExporter) Spans() []*Span {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	return append([]*Span(nil), exporter.spans...)
+}
+
+// Reset drops the kept spans.
+func (exporter *MemoryExporter) Reset() {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	exporter.spans = nil
+}
+
+// Close implements Exporter; the kept spans stay readable.
+func (exporter *MemoryExporter) Close() error {
+	return nil
+}
+
+// NewJSONLinesExporter creates an exporter writing to writer, e.g. a file from os.Create.
+// Close closes the writer if it is an io.Closer.
+func NewJSONLinesExporter(writer io.Writer) *JSONLinesExporter {
+	return &JSONLinesExporter{writer: writer}
+}
+
+// Export implements Exporter.
+func (exporter *JSONLinesExporter) Export(span *Span) error {
+	span.mu.Lock()
+	line, err := json.Marshal(span)
+	span.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+	_, err = exporter.writer.Write(append(line, '\n'))
+	return err
+}
+
+// Close implements Exporter.
+func (exporter *JSONLinesExporter) Close() error {
+	return closeWriter(exporter.writer)
+}
+
+// NewChromeExporter creates an exporter writing to writer, e.g. a file from os.Create.
+// Close closes the writer if it is an io.Closer.
+func NewChromeExporter(writer io.Writer) *ChromeExporter {
+	return &ChromeExporter{writer: writer, rows: make(map[string]int), next: 1}
+}
+
+// Export implements Exporter.
+func (exporter *ChromeExporter) Export(span *Span) error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	if exporter.closed {
+		return ErrExporterClosed
+	}
+	row := exporter.row(span)
+
+	span.mu.Lock()
+	args := make(map[string]any, len(span.Attributes)+3)
+	for key, value := range span.Attributes {
+		args[key] = value
+	}
+	args["trace_id"], args["span_id"] = span.TraceID, span.SpanID
+	if span.Error != "" {
+		args["error"] = span.Error
+	}
+	events := []chromeEvent{{
+		Name:      span.Name,
+		Category:  "function",
+		Phase:     "X",
+		Timestamp: span.Start.UnixMicro(),
+		Duration:  max(span.Duration().Microseconds(), 1),
+		Process:   1,
+		Thread:    row,
+		Args:      args,
+	}}
+	for _, event := range span.Events {
+		events = append(events, chromeEvent{
+			Name:      span.Name + ": " + event.Name,
+			Category:  "event",
+			Phase:     "i",
+			Timestamp: event.Time.UnixMicro(),
+			Scope:     "t",
+			Process:   1,
+			Thread:    row,
+			Args:      event.Attributes,
+		})
+	}
+	span.mu.Unlock()
+
+	for _, event := range events {
+		encoded, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		separator := ",\n"
+		if !exporter.written {
+			separator = "[\n"
+			exporter.written = true
+		}
+		if _, err := io.WriteString(exporter.writer, separator+string(encoded)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements Exporter; it terminates the JSON array.
+func (exporter *ChromeExporter) Close() error {
+	exporter.mu.Lock()
+	defer exporter.mu.Unlock()
+
+	if exporter.closed {
+		return nil
+	}
+	exporter.closed = true
+	closing := "\n]\n"
+	if !exporter.written {
+		closing = "[]\n"
+	}
+	exporter.written = true
+	if _, err := io.WriteString(exporter.writer, closing); err != nil {
+		return err
+	}
+	return closeWriter(exporter.writer)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// row returns the row of the span's trace, releasing it for later traces when the span is the root
+// (spans end before their parent, so the root is the last one of its trace).
+func (exporter *ChromeExporter) row(span *Span) int {
+	row, exists := exporter.rows[span.TraceID]
+	if !exists {
+		if last := len(exporter.free) - 1; last >= 0 {
+			row, exporter.free = exporter.free[last], exporter.free[:last]
+		} else {
+			row = exporter.next
+			exporter.next++
+		}
+	}
+
+	switch {
+	case span.ParentID == "":
+		delete(exporter.rows, span.TraceID)
+		exporter.free = append(exporter.free, row)
+	case !exists:
+		exporter.rows[span.TraceID] = row
+	}
+	return row
+}
+
+// closeWriter closes writer if it is an io.Closer.
+func closeWriter(writer io.Writer) error {
+	if closer, ok := writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package tracing - span defines the unit of work recorded per invocation and its context propagation
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Span records a single invocation of a traced function.
+type Span struct {
+	TraceID    string         `json:"trace_id"`             // TraceID is shared by every span of a call tree.
+	SpanID     string         `json:"span_id"`              // SpanID identifies the span.
+	ParentID   string         `json:"parent_id,omitempty"`  // ParentID is the SpanID of the calling span (empty for roots).
+	Name       string         `json:"name"`                 // Name is the traced function name.
+	Start      time.Time      `json:"start"`                // Start is when the invocation began.
+	End        time.Time      `json:"end"`                  // End is when the invocation finished.
+	Attributes map[string]any `json:"attributes,omitempty"` // Attributes describe the invocation, e.g. "arg.0" or "error".
+	Events     []Event        `json:"events,omitempty"`     // Events are timestamped occurrences, e.g. "panic".
+	Error      string         `json:"error,omitempty"`      // Error is the error or panic message of a failed invocation.
+
+	mu sync.Mutex
+}
+
+// Event is a timestamped occurrence within a span.
+type Event struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// spanKey is the context.Context key of the current span.
+type spanKey struct{}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// ContextWithSpan returns a copy of ctx carrying span as the parent of spans started from it.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, span)
+}
+
+// SpanFromContext returns the span carried by ctx, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// SetAttribute sets an attribute; it is safe to call from the traced function.
+func (span *Span) SetAttribute(key string, value any) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	if span.Attributes == nil {
+		span.Attributes = make(map[string]any)
+	}
+	span.Attributes[key] = value
+}
+
+// AddEvent records an event at the current time; it is safe to call from the traced function.
+func (span *Span) AddEvent(name string, attributes map[string]any) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.Events = append(span.Events, Event{Name: name, Time: time.Now(), Attributes: attributes})
+}
+
+// Duration returns the time between Start and End.
+func (span *Span) Duration() time.Duration {
+	return span.End.Sub(span.Start)
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// newSpan starts a span, as a child of parent if not nil.
+func newSpan(name string, parent *Span) *Span {
+	span := &Span{Name: name, SpanID: fmt.Sprintf("%016x", rand.Uint64()), Start: time.Now()}
+	if parent != nil {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = fmt.Sprintf("%016x%016x", rand.Uint64(), rand.Uint64())
+	}
+	return span
+}
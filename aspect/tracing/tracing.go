@@ -0,0 +1,160 @@
+// Package tracing - tracing provides an aspect recording a span per invocation, linked to the
+// parent span carried in the wrapped function's context.Context
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// SpanKey is the aspect.Context metadata key holding the span of a call.
+const SpanKey = "tracing.span"
+
+const defaultName = "tracing"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Exporter receives finished spans. Implementations must be safe for concurrent use.
+type Exporter interface {
+	// Export records a finished span.
+	Export(span *Span) error
+	// Close flushes and releases the exporter.
+	Close() error
+}
+
+// Config configures a Tracer. Zero values fall back to defaults.
+type Config struct {
+	Name      string     // Name of the advice, for runtime switches (default "tracing").
+	Priority  int        // Priority of the Around advice; keep it highest to time the whole chain.
+	Exporters []Exporter // Exporters receive every finished span (required).
+	OmitArgs  bool       // OmitArgs leaves the "arg.<index>" attributes out of the spans.
+
+	// OnExportError is called when an exporter fails (optional).
+	OnExportError func(span *Span, err error)
+}
+
+// Tracer is an aspect recording a span per invocation. Functions taking a context.Context as
+// first argument receive one carrying their span, so wrapped calls made with it become child spans.
+type Tracer struct {
+	config Config
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Tracer, filling unset configuration with defaults.
+func New(config Config) *Tracer {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	return &Tracer{config: config}
+}
+
+// Name returns the advice name.
+func (tracer *Tracer) Name() string {
+	return tracer.config.Name
+}
+
+// Advice returns the Around advice recording the span.
+func (tracer *Tracer) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     tracer.config.Name,
+			Type:     aspect.Around,
+			Priority: tracer.config.Priority,
+			Handler:  tracer.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (tracer *Tracer) Init() error {
+	if len(tracer.config.Exporters) == 0 {
+		return fmt.Errorf("tracer '%s': at least one exporter is required", tracer.config.Name)
+	}
+	return nil
+}
+
+// Close closes the exporters.
+func (tracer *Tracer) Close() error {
+	var errs []error
+	for _, exporter := range tracer.config.Exporters {
+		errs = append(errs, exporter.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Current returns the span of a call, or nil if it is not traced.
+func Current(ctx *aspect.Context) *Span {
+	span, _ := ctx.Metadata[SpanKey].(*Span)
+	return span
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around starts a span under the caller's span, passes it on through the context argument,
+// and exports it once the call returns or panics.
+func (tracer *Tracer) around(ctx *aspect.Context) error {
+	parent := ctx.Context()
+	span := newSpan(ctx.FunctionName, SpanFromContext(parent))
+	ctx.Metadata[SpanKey] = span
+
+	if len(ctx.Args) > 0 {
+		if _, acceptsContext := ctx.Args[0].(context.Context); acceptsContext {
+			ctx.Args[0] = ContextWithSpan(parent, span)
+			defer func() { ctx.Args[0] = parent }()
+		}
+	}
+	if !tracer.config.OmitArgs {
+		for index, arg := range aspect.Redacted(ctx).Args {
+			if _, isContext := arg.(context.Context); !isContext {
+				span.SetAttribute("arg."+strconv.Itoa(index), fmt.Sprintf("%v", arg))
+			}
+		}
+	}
+
+	defer func() {
+		if panicValue := recover(); panicValue != nil {
+			span.AddEvent("panic", map[string]any{"value": fmt.Sprintf("%v", panicValue), "stack": string(debug.Stack())})
+			tracer.finish(span, fmt.Sprintf("panic: %v", panicValue))
+			panic(panicValue)
+		}
+	}()
+
+	_ = ctx.Proceed()
+
+	var message string
+	if ctx.Error != nil {
+		message = ctx.Error.Error()
+		span.AddEvent("error", map[string]any{"message": message})
+	}
+	if ctx.Skipped {
+		span.SetAttribute("skipped", true)
+	}
+	if ctx.Degraded {
+		span.SetAttribute("degraded", true)
+	}
+	tracer.finish(span, message)
+	return nil
+}
+
+// finish ends the span with an error message (empty on success) and hands it to every exporter.
+func (tracer *Tracer) finish(span *Span, message string) {
+	span.mu.Lock()
+	span.End = time.Now()
+	span.Error = message
+	span.mu.Unlock()
+
+	for _, exporter := range tracer.config.Exporters {
+		if err := exporter.Export(span); err != nil && tracer.config.OnExportError != nil {
+			tracer.config.OnExportError(span, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package tracing - tracing_test validates span nesting, recorded outcomes and the exporters
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// setup registers functions on a fresh global registry and applies a Tracer with the given exporters.
+func setup(t *testing.T, exporters []Exporter, names ...string) {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name, aspect.WithRedactedArgs(1))
+	}
+	registry.MustApply(aspect.On(names...), New(Config{Exporters: exporters}))
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestTracer_NestedSpans(t *testing.T) {
+	memory := NewMemoryExporter(0)
+	setup(t, []Exporter{memory}, "Handle", "Query")
+
+	query := aspect.Wrap2RE("Query", func(ctx context.Context, password string) (int, error) {
+		SpanFromContext(ctx).SetAttribute("rows", 3)
+		return 3, nil
+	})
+	handle := aspect.Wrap1E("Handle", func(ctx context.Context) error {
+		_, err := query(ctx, "secret")
+		return err
+	})
+
+	if err := handle(context.Background()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	spans := memory.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	child, root := spans[0], spans[1]
+	if root.Name != "Handle" || root.ParentID != "" || child.Name != "Query" {
+		t.Fatalf("expected Query to finish inside Handle, got %q then %q", child.Name, root.Name)
+	}
+	if child.TraceID != root.TraceID || child.ParentID != root.SpanID {
+		t.Errorf("expected Query to be a child of Handle, got trace %s parent %s", child.TraceID, child.ParentID)
+	}
+	if child.Attributes["rows"] != 3 || child.Attributes["arg.1"] != aspect.RedactedMask {
+		t.Errorf("expected custom and redacted attributes, got %v", child.Attributes)
+	}
+	if _, recorded := child.Attributes["arg.0"]; recorded {
+		t.Error("expected the context argument to stay out of the attributes")
+	}
+	if child.Start.Before(root.Start) || child.End.After(root.End) {
+		t.Error("expected the child span within its parent")
+	}
+}
+
+func TestTracer_ErrorsAndPanics(t *testing.T) {
+	memory := NewMemoryExporter(0)
+	setup(t, []Exporter{memory}, "Fail", "Crash")
+	fail := aspect.Wrap0RE("Fail", func() (int, error) { return 0, errors.New("no rows") })
+	crash := aspect.Wrap0("Crash", func() { panic("boom") })
+
+	_, _ = fail()
+	func() {
+		defer func() { _ = recover() }()
+		crash()
+	}()
+
+	spans := memory.Spans()
+	if spans[0].Error != "no rows" || spans[0].Events[0].Name != "error" {
+		t.Errorf("expected error span, got %q with events %v", spans[0].Error, spans[0].Events)
+	}
+	if spans[1].Error != "panic: boom" || spans[1].Events[0].Name != "panic" || spans[1].Events[0].Attributes["stack"] == "" {
+		t.Errorf("expected panic span with stack, got %q", spans[1].Error)
+	}
+}
+
+func TestExporters_FileFormats(t *testing.T) {
+	var lines, chrome bytes.Buffer
+	jsonLines, chromeTrace := NewJSONLinesExporter(&lines), NewChromeExporter(&chrome)
+	setup(t, []Exporter{jsonLines, chromeTrace}, "Work")
+
+	work := aspect.Wrap1("Work", func(ctx context.Context) {})
+	work(context.Background())
+	work(context.Background())
+	if err := chromeTrace.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := strings.Split(strings.TrimSpace(lines.String()), "\n")
+	if len(records) != 2 {
+		t.Fatalf("expected one JSON line per span, got %d", len(records))
+	}
+	var span Span
+	if err := json.Unmarshal([]byte(records[0]), &span); err != nil || span.Name != "Work" || span.TraceID == "" {
+		t.Fatalf("expected a decodable span, got %q (%v)", span.Name, err)
+	}
+
+	var events []chromeEvent
+	if err := json.Unmarshal(chrome.Bytes(), &events); err != nil {
+		t.Fatalf("expected a valid Chrome trace, got %v:\n%s", err, chrome.String())
+	}
+	if len(events) != 2 || events[0].Phase != "X" || events[0].Thread != events[1].Thread {
+		t.Fatalf("expected 2 complete events, the second trace reusing the row of the ended first one, got %+v", events)
+	}
+}
+
+func TestChromeExporter_RowsOfTracesInProgress(t *testing.T) {
+	var chrome bytes.Buffer
+	exporter := NewChromeExporter(&chrome)
+	export := func(traceID, spanID, parentID string) int {
+		t.Helper()
+		before := chrome.Len()
+		if err := exporter.Export(&Span{TraceID: traceID, SpanID: spanID, ParentID: parentID, Name: spanID}); err != nil {
+			t.Fatal(err)
+		}
+		var event chromeEvent
+		if err := json.Unmarshal(bytes.TrimLeft(chrome.Bytes()[before:], "[,\n"), &event); err != nil {
+			t.Fatal(err)
+		}
+		return event.Thread
+	}
+
+	first, second := export("a", "a.child", "a.root"), export("b", "b.child", "b.root")
+	if first == second {
+		t.Fatalf("expected concurrent traces on separate rows, got %d", first)
+	}
+	if root := export("a", "a.root", ""); root != first {
+		t.Fatalf("expected the root on its trace's row %d, got %d", first, root)
+	}
+	if third := export("c", "c.root", ""); third != first {
+		t.Fatalf("expected a new trace to reuse the row %d of the ended one, got %d", first, third)
+	}
+	export("b", "b.root", "")
+	if len(exporter.rows) != 0 {
+		t.Fatalf("expected ended traces to release their rows, got %v", exporter.rows)
+	}
+
+	if err := exporter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := exporter.Export(&Span{TraceID: "d", SpanID: "d.root"}); !errors.Is(err, ErrExporterClosed) {
+		t.Fatalf("expected exporting after Close to fail, got %v", err)
+	}
+}
+
+func TestTracer_InitRequiresExporter(t *testing.T) {
+	if err := New(Config{}).Init(); err == nil {
+		t.Fatal("expected error without exporters")
+	}
+}
@@ -0,0 +1,45 @@
+// Package watchdog - stack captures the stack of a watched call from a goroutine profile, finding
+// its goroutine by a profiler label so nothing is captured until a threshold is crossed
+package watchdog
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strconv"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// labelKey is the profiler label marking the goroutine of a watched call.
+const labelKey = "gosaidsno_watchdog"
+
+// aroundFrame is part of the frame of the Around advice in a goroutine profile; goroutines started
+// by the call inherit its label but not this frame.
+var aroundFrame = []byte("watchdog.(*Watchdog).around")
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// labeled runs proceed with the label of a watched call added to the labels of the goroutine,
+// see aspect.Context.DoLabeled.
+func labeled(ctx *aspect.Context, id uint64, proceed func()) {
+	ctx.DoLabeled(pprof.Labels(labelKey, strconv.FormatUint(id, 10)), func(context.Context) { proceed() })
+}
+
+// goroutineStack returns the stack of the watched call with the given ID, or "" if it has completed.
+func goroutineStack(id uint64) string {
+	var profile bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return ""
+	}
+
+	label := []byte(strconv.Quote(labelKey) + ":" + strconv.Quote(strconv.FormatUint(id, 10)))
+	for _, record := range bytes.Split(profile.Bytes(), []byte("\n\n")) {
+		if bytes.Contains(record, label) && bytes.Contains(record, aroundFrame) {
+			return string(record)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,194 @@
+// Package watchdog - watchdog provides an aspect reporting calls that exceed a threshold while they
+// are still running, with the stack of their goroutine, and again once they complete
+package watchdog
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+const defaultName = "watchdog"
+
+// -------------------------------------------- Types --------------------------------------------
+
+// Report describes a slow call. Args and Metadata are copies taken when the call started, so they
+// can be read while the call is still running.
+type Report struct {
+	FunctionName string         // FunctionName is the registered function name.
+	Args         []any          // Args are the arguments of the call.
+	Metadata     map[string]any // Metadata is the metadata set by advice that ran before the watchdog.
+	Threshold    time.Duration  // Threshold is the configured limit.
+	Elapsed      time.Duration  // Elapsed is the time spent so far in OnSlow, and the final duration in OnComplete.
+	Stack        string         // Stack is the call's goroutine stack when the threshold was crossed, as in a goroutine profile.
+}
+
+// Stats are the watchdog counters of a single function.
+type Stats struct {
+	Slow    uint64 // Slow are calls that crossed the threshold.
+	Running int64  // Running are slow calls that have not completed yet; a stuck call stays counted.
+}
+
+// Config configures a Watchdog. Zero values fall back to defaults.
+type Config struct {
+	Name      string        // Name of the advice, for runtime switches (default "watchdog").
+	Priority  int           // Priority of the Around advice; keep it highest to watch the whole chain.
+	Threshold time.Duration // Threshold is the duration after which a running call is reported (required).
+
+	// OnSlow is called from a timer goroutine when a call crosses the threshold (required).
+	OnSlow func(report Report)
+	// OnComplete is called by slow calls when they eventually complete, after OnSlow returned (optional).
+	OnComplete func(report Report)
+}
+
+// Watchdog is an aspect arming a timer per invocation of the functions it is applied to. Watched
+// calls run with a profiler label identifying them, added to their other labels.
+type Watchdog struct {
+	config Config
+	calls  atomic.Uint64 // calls numbers the watched invocations for their profiler label.
+
+	mu    sync.Mutex
+	stats map[string]*counters
+}
+
+// counters are the live Stats of a function.
+type counters struct {
+	slow    atomic.Uint64
+	running atomic.Int64
+}
+
+// watch is the state of a single watched invocation.
+type watch struct {
+	mu        sync.Mutex
+	completed bool
+	fired     bool
+	reported  chan struct{} // reported is closed once OnSlow returned, to order it before OnComplete.
+	report    Report
+}
+
+// -------------------------------------------- Public Functions --------------------------------------------
+
+// New creates a Watchdog, filling unset configuration with defaults.
+func New(config Config) *Watchdog {
+	if config.Name == "" {
+		config.Name = defaultName
+	}
+	return &Watchdog{config: config, stats: make(map[string]*counters)}
+}
+
+// Name returns the advice name.
+func (watchdog *Watchdog) Name() string {
+	return watchdog.config.Name
+}
+
+// Advice returns the Around advice watching the call.
+func (watchdog *Watchdog) Advice() []aspect.Advice {
+	return []aspect.Advice{
+		{
+			Name:     watchdog.config.Name,
+			Type:     aspect.Around,
+			Priority: watchdog.config.Priority,
+			Handler:  watchdog.around,
+		},
+	}
+}
+
+// Init validates the configuration.
+func (watchdog *Watchdog) Init() error {
+	if watchdog.config.Threshold <= 0 {
+		return fmt.Errorf("watchdog '%s': threshold must be positive", watchdog.config.Name)
+	}
+	if watchdog.config.OnSlow == nil {
+		return fmt.Errorf("watchdog '%s': slow call callback is required", watchdog.config.Name)
+	}
+	return nil
+}
+
+// Close implements aspect.Aspect; pending timers stop with their calls.
+func (watchdog *Watchdog) Close() error {
+	return nil
+}
+
+// Stats returns the counters of a function.
+func (watchdog *Watchdog) Stats(functionName string) Stats {
+	counters := watchdog.counters(functionName)
+	return Stats{Slow: counters.slow.Load(), Running: counters.running.Load()}
+}
+
+// -------------------------------------------- Private Helper Functions --------------------------------------------
+
+// around arms the timer, proceeds, and reports the completion of slow calls, including panicking ones.
+func (watchdog *Watchdog) around(ctx *aspect.Context) error {
+	counters := watchdog.counters(ctx.FunctionName)
+	id := watchdog.calls.Add(1)
+	start := time.Now()
+	state := &watch{
+		reported: make(chan struct{}),
+		report: Report{
+			FunctionName: ctx.FunctionName,
+			Args:         slices.Clone(ctx.Args),
+			Metadata:     maps.Clone(ctx.Metadata),
+			Threshold:    watchdog.config.Threshold,
+		},
+	}
+
+	timer := time.AfterFunc(watchdog.config.Threshold, func() {
+		state.mu.Lock()
+		if state.completed {
+			state.mu.Unlock()
+			return
+		}
+		state.fired = true
+		report := state.report
+		state.mu.Unlock()
+
+		defer close(state.reported)
+		counters.slow.Add(1)
+		counters.running.Add(1)
+		report.Elapsed = time.Since(start)
+		report.Stack = goroutineStack(id)
+		watchdog.config.OnSlow(report)
+	})
+
+	defer func() {
+		timer.Stop()
+		state.mu.Lock()
+		state.completed = true
+		fired := state.fired
+		state.mu.Unlock()
+		if !fired {
+			return
+		}
+
+		<-state.reported
+		counters.running.Add(-1)
+		if watchdog.config.OnComplete != nil {
+			report := state.report
+			report.Elapsed = time.Since(start)
+			watchdog.config.OnComplete(report)
+		}
+	}()
+
+	labeled(ctx, id, func() { _ = ctx.Proceed() })
+	return nil
+}
+
+// counters returns the counters of a function, creating them on first use.
+func (watchdog *Watchdog) counters(functionName string) *counters {
+	watchdog.mu.Lock()
+	defer watchdog.mu.Unlock()
+
+	functionCounters, exists := watchdog.stats[functionName]
+	if !exists {
+		functionCounters = &counters{}
+		watchdog.stats[functionName] = functionCounters
+	}
+	return functionCounters
+}
@@ -0,0 +1,189 @@
+// Package watchdog - watchdog_test validates slow-call reports while running and on completion
+package watchdog
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/profiling"
+)
+
+// -------------------------------------------- Test Helpers --------------------------------------------
+
+// stuckInWatchedCall is a recognizable frame for the stack assertion.
+func stuckInWatchedCall(release <-chan struct{}) {
+	<-release
+}
+
+// stuckInOtherCall is a recognizable frame of a concurrent watched call.
+func stuckInOtherCall(release <-chan struct{}) {
+	<-release
+}
+
+// setup registers functions on a fresh global registry and applies the watchdog to them.
+func setup(t *testing.T, applied aspect.Aspect, names ...string) *aspect.Registry {
+	t.Helper()
+	original := aspect.GetGlobalRegistry()
+	registry := aspect.NewRegistry()
+	aspect.SetGlobalRegistry(registry)
+	t.Cleanup(func() { aspect.SetGlobalRegistry(original) })
+
+	for _, name := range names {
+		registry.MustRegister(name)
+	}
+	registry.MustApply(aspect.On(names...), applied)
+	return registry
+}
+
+// -------------------------------------------- Tests --------------------------------------------
+
+func TestWatchdog_ReportsWhileRunningAndOnCompletion(t *testing.T) {
+	var mu sync.Mutex
+	var slow, completed []Report
+	slowSeen := make(chan struct{})
+	watchdog := New(Config{
+		Threshold: 10 * time.Millisecond,
+		OnSlow: func(report Report) {
+			mu.Lock()
+			defer mu.Unlock()
+			slow = append(slow, report)
+			close(slowSeen)
+		},
+		OnComplete: func(report Report) {
+			mu.Lock()
+			defer mu.Unlock()
+			completed = append(completed, report)
+		},
+	})
+	setup(t, watchdog, "Export")
+
+	release := make(chan struct{})
+	export := aspect.Wrap1("Export", func(report string) { stuckInWatchedCall(release) })
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		export("quarterly")
+	}()
+
+	select {
+	case <-slowSeen:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the slow call to be reported while running")
+	}
+	if stats := watchdog.Stats("Export"); stats.Slow != 1 || stats.Running != 1 {
+		t.Errorf("expected 1 running slow call, got %+v", stats)
+	}
+
+	mu.Lock()
+	report := slow[0]
+	mu.Unlock()
+	if report.FunctionName != "Export" || report.Args[0] != "quarterly" || report.Elapsed < 10*time.Millisecond {
+		t.Errorf("unexpected slow report %+v", report)
+	}
+	if !strings.Contains(report.Stack, "stuckInWatchedCall") {
+		t.Errorf("expected the call's goroutine stack, got:\n%s", report.Stack)
+	}
+
+	close(release)
+	<-done
+
+	if len(completed) != 1 || completed[0].Elapsed < report.Elapsed {
+		t.Fatalf("expected a completion report with the final duration, got %+v", completed)
+	}
+	if stats := watchdog.Stats("Export"); stats.Running != 0 {
+		t.Errorf("expected no running slow calls, got %+v", stats)
+	}
+}
+
+func TestWatchdog_ReportsSnapshotAndOwnStack(t *testing.T) {
+	reports := make(chan Report, 2)
+	watchdog := New(Config{Threshold: 10 * time.Millisecond, Priority: 10, OnSlow: func(report Report) { reports <- report }})
+	registry := setup(t, watchdog, "Export", "Import")
+	for _, functionName := range []string{"Export", "Import"} {
+		for priority, tenant := range map[int]string{20: "acme", 0: "changed"} {
+			registry.MustAddAdvice(functionName, aspect.Advice{
+				Type:     aspect.Around,
+				Priority: priority,
+				Handler: func(ctx *aspect.Context) error {
+					ctx.Metadata["tenant"] = tenant
+					return ctx.Proceed()
+				},
+			})
+		}
+	}
+
+	release := make(chan struct{})
+	export := aspect.Wrap1("Export", func(ctx context.Context) { stuckInWatchedCall(release) })
+	importer := aspect.Wrap1("Import", func(ctx context.Context) { stuckInOtherCall(release) })
+	var wg sync.WaitGroup
+	for _, call := range []func(context.Context){export, importer} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			call(context.Background())
+		}()
+	}
+	defer wg.Wait()
+	defer close(release)
+
+	for range 2 {
+		report := <-reports
+		frame, other := "stuckInWatchedCall", "stuckInOtherCall"
+		if report.FunctionName == "Import" {
+			frame, other = other, frame
+		}
+		if !strings.Contains(report.Stack, frame) || strings.Contains(report.Stack, other) {
+			t.Errorf("expected only the stack of '%s', got:\n%s", report.FunctionName, report.Stack)
+		}
+		if report.Metadata["tenant"] != "acme" {
+			t.Errorf("expected metadata as of the start of the watch, got %v", report.Metadata)
+		}
+	}
+}
+
+func TestWatchdog_KeepsProfilingLabels(t *testing.T) {
+	reports := make(chan Report, 1)
+	registry := setup(t, New(Config{Threshold: 10 * time.Millisecond, OnSlow: func(report Report) { reports <- report }}), "Export")
+	registry.MustApply(aspect.On("Export"), profiling.New(profiling.Config{Priority: 10}))
+
+	release := make(chan struct{})
+	export := aspect.Wrap0("Export", func() { stuckInWatchedCall(release) })
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		export()
+	}()
+	defer func() { <-done }()
+	defer close(release)
+
+	if report := <-reports; !strings.Contains(report.Stack, `"function":"Export"`) {
+		t.Errorf("expected the profiling label to stay on the watched goroutine, got:\n%s", report.Stack)
+	}
+}
+
+func TestWatchdog_FastCallsAreNotReported(t *testing.T) {
+	reported := false
+	watchdog := New(Config{Threshold: time.Second, OnSlow: func(Report) { reported = true }})
+	setup(t, watchdog, "Fast")
+
+	aspect.Wrap0("Fast", func() {})()
+	time.Sleep(5 * time.Millisecond)
+
+	if reported || watchdog.Stats("Fast").Slow != 0 {
+		t.Fatal("expected fast calls not to be reported")
+	}
+}
+
+func TestWatchdog_InitValidation(t *testing.T) {
+	if err := New(Config{OnSlow: func(Report) {}}).Init(); err == nil {
+		t.Error("expected error without threshold")
+	}
+	if err := New(Config{Threshold: time.Second}).Init(); err == nil {
+		t.Error("expected error without callback")
+	}
+}
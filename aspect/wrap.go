@@ -1,174 +1,161 @@
-// Package aspect - wrap provides function wrapping utilities with AOP advice execution
+// Package aspect - wrap provides function wrapping utilities with AOP advice execution.
+// Wrapped functions call the target with the Context arguments and return the results and error
+// held by the Context after advice ran, so advice can replace arguments before the call,
+// Around/AfterReturning advice can replace results, and rejected calls surface their error.
+// Wrappers without an error return panic with the error of calls advice rejected (e.g. ErrShutdown
+// or an open circuit) rather than return zero values, and advice leaving an argument or result of
+// the wrong type is reported rather than zeroed.
 package aspect
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// -------------------------------------------- Constants & Variables --------------------------------------------
+
+// ErrTypeMismatch is matched (via errors.Is) by errors of calls whose advice left an argument or result
+// of a type the wrapped function cannot take or return.
+var ErrTypeMismatch = errors.New("type mismatch")
 
 // -------------------------------------------- Public Functions --------------------------------------------
 
 // Wrap0 wraps a function with no arguments and no return values.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown.
 func Wrap0(name string, fn func()) func() {
 	return func() {
-		executeWithAdvice(name, func(ctx *Context) {
+		panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
 			fn()
-		})
+		}))
 	}
 }
 
 // Wrap0R wraps a function with no arguments and one return value.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown,
+// and with ErrTypeMismatch if advice leaves a result that is not an R.
 func Wrap0R[R any](name string, fn func() R) func() R {
 	return func() R {
-		var result R
-		ctx := executeWithAdvice(name, func(ctx *Context) {
-			result = fn()
-			ctx.SetResult(0, result)
-		})
-
-		// If Around advice set a result and skipped execution, use that result
-		if ctx != nil && len(ctx.Results) > 0 && ctx.Results[0] != nil {
-			result = ctx.Results[0].(R)
-		}
-
-		return result
+		ctx := panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
+			ctx.SetResult(0, fn())
+		}))
+		return resultOf[R](ctx, 0)
 	}
 }
 
 // Wrap0RE wraps a function with no arguments and returns (result, error).
+// A result left by advice that is not an R is returned as an ErrTypeMismatch error.
 func Wrap0RE[R any](name string, fn func() (R, error)) func() (R, error) {
 	return func() (R, error) {
-		var result R
-		var err error
 		ctx := executeWithAdvice(name, func(ctx *Context) {
-			result, err = fn()
+			result, err := fn()
 			ctx.SetResult(0, result)
 			ctx.Error = err
 		})
-
-		// If Around advice set a result and skipped execution, use that result
-		if ctx != nil && len(ctx.Results) > 0 && ctx.Results[0] != nil {
-			result = ctx.Results[0].(R)
-		}
-		if ctx != nil && ctx.Error != nil {
-			err = ctx.Error
-		}
-
-		return result, err
+		return resultAndError[R](ctx)
 	}
 }
 
 // Wrap1 wraps a function with one argument and no return values.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown.
 func Wrap1[A any](name string, fn func(A)) func(A) {
 	return func(a A) {
-		executeWithAdvice(name, func(ctx *Context) {
-			fn(a)
-		}, a)
+		panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
+			fn(argOf[A](ctx, 0))
+		}, a))
 	}
 }
 
 // Wrap1R wraps a function with one argument and one return value.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown,
+// and with ErrTypeMismatch if advice leaves a result that is not an R.
 func Wrap1R[A, R any](name string, fn func(A) R) func(A) R {
 	return func(a A) R {
-		var result R
-		ctx := executeWithAdvice(name, func(ctx *Context) {
-			result = fn(a)
-			ctx.SetResult(0, result)
-		}, a)
-
-		// If Around advice set a result and skipped execution, use that result
-		if ctx != nil && ctx.Skipped && len(ctx.Results) > 0 && ctx.Results[0] != nil {
-			// Safe type assertion with proper handling
-			if res, ok := ctx.Results[0].(R); ok {
-				result = res
-			}
-		}
-
-		return result
+		ctx := panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
+			ctx.SetResult(0, fn(argOf[A](ctx, 0)))
+		}, a))
+		return resultOf[R](ctx, 0)
 	}
 }
 
 // Wrap1RE wraps a function with one argument and returns (result, error).
+// A result left by advice that is not an R is returned as an ErrTypeMismatch error.
 func Wrap1RE[A, R any](name string, fn func(A) (R, error)) func(A) (R, error) {
 	return func(a A) (R, error) {
-		var result R
-		var err error
-		executeWithAdvice(name, func(ctx *Context) {
-			result, err = fn(a)
+		ctx := executeWithAdvice(name, func(ctx *Context) {
+			result, err := fn(argOf[A](ctx, 0))
 			ctx.SetResult(0, result)
 			ctx.Error = err
 		}, a)
-		return result, err
+		return resultAndError[R](ctx)
 	}
 }
 
 // Wrap1E wraps a function with one argument and returns error.
 func Wrap1E[A any](name string, fn func(A) error) func(A) error {
 	return func(a A) error {
-		var err error
-		executeWithAdvice(name, func(ctx *Context) {
-			err = fn(a)
-			ctx.Error = err
+		ctx := executeWithAdvice(name, func(ctx *Context) {
+			ctx.Error = fn(argOf[A](ctx, 0))
 		}, a)
-		return err
+		return ctx.Error
 	}
 }
 
 // Wrap2 wraps a function with two arguments and no return values.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown.
 func Wrap2[A, B any](name string, fn func(A, B)) func(A, B) {
 	return func(a A, b B) {
-		executeWithAdvice(name, func(ctx *Context) {
-			fn(a, b)
-		}, a, b)
+		panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
+			fn(argOf[A](ctx, 0), argOf[B](ctx, 1))
+		}, a, b))
 	}
 }
 
 // Wrap2R wraps a function with two arguments and one return value.
+// The wrapper panics with the call's error if advice rejects it, e.g. with ErrShutdown,
+// and with ErrTypeMismatch if advice leaves a result that is not an R.
 func Wrap2R[A, B, R any](name string, fn func(A, B) R) func(A, B) R {
 	return func(a A, b B) R {
-		var result R
-		executeWithAdvice(name, func(ctx *Context) {
-			result = fn(a, b)
-			ctx.SetResult(0, result)
-		}, a, b)
-		return result
+		ctx := panicOnRejection(executeWithAdvice(name, func(ctx *Context) {
+			ctx.SetResult(0, fn(argOf[A](ctx, 0), argOf[B](ctx, 1)))
+		}, a, b))
+		return resultOf[R](ctx, 0)
 	}
 }
 
 // Wrap2RE wraps a function with two arguments and returns (result, error).
+// A result left by advice that is not an R is returned as an ErrTypeMismatch error.
 func Wrap2RE[A, B, R any](name string, fn func(A, B) (R, error)) func(A, B) (R, error) {
 	return func(a A, b B) (R, error) {
-		var result R
-		var err error
-		executeWithAdvice(name, func(ctx *Context) {
-			result, err = fn(a, b)
+		ctx := executeWithAdvice(name, func(ctx *Context) {
+			result, err := fn(argOf[A](ctx, 0), argOf[B](ctx, 1))
 			ctx.SetResult(0, result)
 			ctx.Error = err
 		}, a, b)
-		return result, err
+		return resultAndError[R](ctx)
 	}
 }
 
 // Wrap2E wraps a function with two arguments and returns error.
 func Wrap2E[A, B any](name string, fn func(A, B) error) func(A, B) error {
 	return func(a A, b B) error {
-		var err error
-		executeWithAdvice(name, func(ctx *Context) {
-			err = fn(a, b)
-			ctx.Error = err
+		ctx := executeWithAdvice(name, func(ctx *Context) {
+			ctx.Error = fn(argOf[A](ctx, 0), argOf[B](ctx, 1))
 		}, a, b)
-		return err
+		return ctx.Error
 	}
 }
 
 // Wrap3RE wraps a function with three arguments and returns (result, error).
+// A result left by advice that is not an R is returned as an ErrTypeMismatch error.
 func Wrap3RE[A, B, C, R any](name string, fn func(A, B, C) (R, error)) func(A, B, C) (R, error) {
 	return func(a A, b B, c C) (R, error) {
-		var result R
-		var err error
-		executeWithAdvice(name, func(ctx *Context) {
-			result, err = fn(a, b, c)
+		ctx := executeWithAdvice(name, func(ctx *Context) {
+			result, err := fn(argOf[A](ctx, 0), argOf[B](ctx, 1), argOf[C](ctx, 2))
 			ctx.SetResult(0, result)
 			ctx.Error = err
 		}, a, b, c)
-		return result, err
+		return resultAndError[R](ctx)
 	}
 }
 
@@ -176,17 +163,41 @@ func Wrap3RE[A, B, C, R any](name string, fn func(A, B, C) (R, error)) func(A, B
 
 // executeWithAdvice executes a function with full advice chain support and returns the context.
 func executeWithAdvice(functionName string, targetFn func(*Context), args ...any) *Context {
+	// Reject new calls once the registry is shutting down
+	registry := GetGlobalRegistry()
+	if err := registry.enter(); err != nil {
+		ctx := NewContext(functionName, args...)
+		ctx.Error = err
+		ctx.Skipped = true
+		return ctx
+	}
+	defer registry.leave()
+
+	// Global kill switch costs a single atomic load
+	if !registry.IsEnabled() {
+		return executeWithoutAdvice(functionName, targetFn, args...)
+	}
+
 	// Get advice chain from registry
-	chain, err := GetAdviceChain(functionName)
+	chain, err := registry.GetAdviceChain(functionName)
 	if err != nil {
 		// No advice registered, just execute target function
-		ctx := NewContext(functionName, args...)
-		targetFn(ctx)
-		return ctx
+		return executeWithoutAdvice(functionName, targetFn, args...)
+	}
+
+	chain.inflight.Add(1)
+	defer chain.inflight.Add(-1)
+
+	if chain.disabled.Load() {
+		// Function switched off, just execute target function
+		return executeWithoutAdvice(functionName, targetFn, args...)
 	}
 
 	// Create execution context
 	ctx := NewContext(functionName, args...)
+	ctx.redaction = chain.redaction
+	ctx.registry = registry
+	chain.calls.Add(1)
 
 	// Defer After advice (always runs)
 	defer func() {
@@ -196,6 +207,7 @@ func executeWithAdvice(functionName string, targetFn func(*Context), args ...any
 	// Defer panic recovery and AfterThrowing advice
 	defer func() {
 		if r := recover(); r != nil {
+			chain.panics.Add(1)
 			ctx.PanicValue = r
 			_ = chain.ExecuteAfterThrowing(ctx)
 
@@ -209,28 +221,82 @@ func executeWithAdvice(functionName string, targetFn func(*Context), args ...any
 		panic(fmt.Errorf("before advice failed: %w", err))
 	}
 
-	// Execute Around advice (if any)
+	// Execute Around advice (if any) as an interceptor chain ending in the target function
 	if chain.HasAround() {
-		if err := chain.ExecuteAround(ctx); err != nil {
+		if err := chain.executeAround(ctx, targetFn); err != nil {
 			panic(fmt.Errorf("around advice failed: %w", err))
 		}
-		// If Around advice sets Skipped, don't execute target function
-		if ctx.Skipped {
-			// Execute AfterReturning if no error (Around advice might have set result)
-			if ctx.Error == nil && !ctx.HasPanic() {
-				_ = chain.ExecuteAfterReturning(ctx)
-			}
-			return ctx
-		}
+	} else {
+		targetFn(ctx)
 	}
 
-	// Execute target function
-	targetFn(ctx)
-
 	// Execute AfterReturning advice (only if no error and no panic)
 	if ctx.Error == nil && !ctx.HasPanic() {
 		_ = chain.ExecuteAfterReturning(ctx)
+	} else if ctx.Error != nil {
+		chain.errors.Add(1)
 	}
 
 	return ctx
 }
+
+// executeWithoutAdvice executes the target function with a bare context and no advice.
+func executeWithoutAdvice(functionName string, targetFn func(*Context), args ...any) *Context {
+	ctx := NewContext(functionName, args...)
+	targetFn(ctx)
+	return ctx
+}
+
+// panicOnRejection panics with the context error if advice left one, e.g. when rejecting the call.
+// Wrappers without an error return use it, since they have no other way to report the dropped call;
+// their target cannot set an error itself.
+func panicOnRejection(ctx *Context) *Context {
+	if ctx.Error != nil {
+		panic(ctx.Error)
+	}
+	return ctx
+}
+
+// argOf returns the context argument at index as A, or the zero value if absent or nil.
+// Panics with ErrTypeMismatch if advice replaced the argument with a value of another type.
+func argOf[A any](ctx *Context, index int) A {
+	var arg A
+	if index >= len(ctx.Args) || ctx.Args[index] == nil {
+		return arg
+	}
+	arg, ok := ctx.Args[index].(A)
+	if !ok {
+		panic(fmt.Errorf("function '%s': argument %d is %T, not %v: %w", ctx.FunctionName, index, ctx.Args[index], reflect.TypeFor[A](), ErrTypeMismatch))
+	}
+	return arg
+}
+
+// resultOf returns the context result at index as R, or the zero value if absent or nil.
+// Panics with ErrTypeMismatch if advice left a result of another type.
+func resultOf[R any](ctx *Context, index int) R {
+	result, err := typedResult[R](ctx, index)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// resultAndError returns the first context result as R together with the context error.
+// A result of another type is reported as an ErrTypeMismatch error, joined with the context error.
+func resultAndError[R any](ctx *Context) (R, error) {
+	result, err := typedResult[R](ctx, 0)
+	if err != nil {
+		return result, errors.Join(err, ctx.Error)
+	}
+	return result, ctx.Error
+}
+
+// typedResult converts the context result at index to R; a missing or nil result is the zero value.
+func typedResult[R any](ctx *Context, index int) (R, error) {
+	value := ctx.GetResult(index)
+	result, ok := value.(R)
+	if !ok && value != nil {
+		return result, fmt.Errorf("function '%s': result %d is %T, not %v: %w", ctx.FunctionName, index, value, reflect.TypeFor[R](), ErrTypeMismatch)
+	}
+	return result, nil
+}
@@ -4,51 +4,22 @@ package main
 import (
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/cache"
 	"github.com/seyedali-dev/gosaidsno/examples/utils"
 )
 
-// -------------------------------------------- Simple Cache --------------------------------------------
+// -------------------------------------------- Caches --------------------------------------------
 
-type Cache struct {
-	mu   sync.RWMutex
-	data map[string]interface{}
-	hits int
-	miss int
-}
-
-func NewCache() *Cache {
-	return &Cache{data: make(map[string]interface{})}
-}
-
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	val, ok := c.data[key]
-	if ok {
-		c.hits++
-	} else {
-		c.miss++
-	}
-	return val, ok
-}
-
-func (c *Cache) Set(key string, value interface{}) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.data[key] = value
-}
-
-func (c *Cache) Stats() (hits, miss int) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.hits, c.miss
-}
+var (
+	// userCache keeps profiles until evicted, holding at most 100 users
+	userCache = cache.New(cache.Config{Name: "user-cache", Priority: 100, MaxEntries: 100, Eviction: cache.LRU})
 
-var userCache = NewCache()
+	// recommendationCache keeps recommendations fresh for 5s
+	recommendationCache = cache.New(cache.Config{Name: "recommendation-cache", Priority: 100, TTL: 5 * time.Second})
+)
 
 // -------------------------------------------- Setup --------------------------------------------
 
@@ -58,134 +29,29 @@ func setupAOP() {
 	aspect.MustRegister("FetchUserProfile")
 	aspect.MustRegister("CalculateRecommendations")
 
-	// Around advice for caching
-	aspect.MustAddAdvice("FetchUserProfile", aspect.Advice{
-		Type:     aspect.Around,
-		Priority: 100,
-		Handler: func(ctx *aspect.Context) error {
-			utils.LogAround(ctx, 100, "CACHE CHECK")
-			userID := ctx.Args[0].(string)
-			cacheKey := "user:" + userID
-
-			// Check cache
-			if cached, ok := userCache.Get(cacheKey); ok {
-				log.Printf("   💾 [CACHE HIT] FetchUserProfile(%s) - returning cached result", userID)
-				ctx.SetResult(0, cached)
-				ctx.Skipped = true // Skip expensive DB call
-				log.Printf("   ⏩ [AROUND] Skipping function execution due to cache hit")
-				log.Printf("🟠 [AROUND] %s - END (cache hit)", ctx.FunctionName)
-				return nil
-			}
-
-			log.Printf("   💾 [CACHE MISS] FetchUserProfile(%s) - will fetch from DB", userID)
-			log.Printf("   ▶️  [AROUND] Proceeding with function execution")
-			utils.LogAround(ctx, 100, "END (cache miss)")
-			return nil // Allow function to execute
-		},
-	})
-
-	// AfterReturning to populate cache (only when function actually executed)
-	aspect.MustAddAdvice("FetchUserProfile", aspect.Advice{
-		Type:     aspect.AfterReturning,
-		Priority: 100,
-		Handler: func(ctx *aspect.Context) error {
-			utils.LogAfterReturning(ctx, 100, "CACHE POPULATION")
-
-			// Don't cache if execution was skipped (cache hit)
-			if ctx.Skipped {
-				log.Printf("   ⏭️  [CACHE] Skipping cache set - function was not executed")
-				return nil
-			}
-
-			userID := ctx.Args[0].(string)
-			if len(ctx.Results) == 0 || ctx.Results[0] == nil {
-				log.Printf("   ⚠️  [CACHE] No result to cache")
-				return nil // No result to cache
-			}
-
-			profile := ctx.Results[0].(*UserProfile)
-			cacheKey := "user:" + userID
-
-			userCache.Set(cacheKey, profile)
-			log.Printf("   💾 [CACHE SET] Stored user profile for %s", userID)
-			return nil
-		},
-	})
-
-	// Time-based cache with TTL
-	cacheStore := make(map[string]cacheEntry)
-	var cacheMu sync.RWMutex
-
-	aspect.MustAddAdvice("CalculateRecommendations", aspect.Advice{
-		Type:     aspect.Around,
-		Priority: 100,
-		Handler: func(ctx *aspect.Context) error {
-			utils.LogAround(ctx, 100, "TTL CACHE CHECK")
-			userID := ctx.Args[0].(string)
-
-			cacheMu.RLock()
-			entry, exists := cacheStore[userID]
-			cacheMu.RUnlock()
-
-			// Check if cache is valid (not expired)
-			if exists && time.Since(entry.timestamp) < 5*time.Second {
-				log.Printf("   💾 [CACHE HIT] CalculateRecommendations(%s) - using cached", userID)
-				ctx.SetResult(0, entry.value)
-				ctx.Skipped = true
-				log.Printf("   ⏩ [AROUND] Skipping function execution due to cache hit")
-				utils.LogAround(ctx, 100, "END (cache hit)")
-				return nil
-			}
-
-			if exists {
-				log.Printf("   💾 [CACHE EXPIRED] CalculateRecommendations(%s) - recalculating", userID)
-			} else {
-				log.Printf("   💾 [CACHE MISS] CalculateRecommendations(%s) - calculating", userID)
-			}
-			log.Printf("   ▶️  [AROUND] Proceeding with function execution")
-			utils.LogAround(ctx, 100, "END (cache miss/expired)")
-			return nil
-		},
-	})
-
-	aspect.MustAddAdvice("CalculateRecommendations", aspect.Advice{
-		Type:     aspect.AfterReturning,
-		Priority: 100,
-		Handler: func(ctx *aspect.Context) error {
-			utils.LogAfterReturning(ctx, 100, "TTL CACHE POPULATION")
-
-			// Don't cache if execution was skipped
-			if ctx.Skipped {
-				log.Printf("   ⏭️  [CACHE] Skipping cache set - function was not executed")
-				return nil
-			}
-
-			userID := ctx.Args[0].(string)
-			if len(ctx.Results) == 0 || ctx.Results[0] == nil {
-				log.Printf("   ⚠️  [CACHE] No result to cache")
-				return nil
-			}
-
-			recommendations := ctx.Results[0].([]string)
-
-			cacheMu.Lock()
-			cacheStore[userID] = cacheEntry{
-				value:     recommendations,
-				timestamp: time.Now(),
-			}
-			cacheMu.Unlock()
-
-			log.Printf("   💾 [CACHE SET] Stored recommendations for %s (TTL: 5s)", userID)
-			return nil
-		},
-	})
+	// Around advice for caching, keyed by the user ID argument
+	aspect.MustApply(aspect.On("FetchUserProfile"), userCache)
+	aspect.MustApply(aspect.On("CalculateRecommendations"), recommendationCache)
+
+	// After advice reporting whether the call was served from the cache
+	aspect.MustAddAdvice("FetchUserProfile", cacheLog)
+	aspect.MustAddAdvice("CalculateRecommendations", cacheLog)
 
 	log.Println("=== AOP Setup Complete ===\n")
 }
 
-type cacheEntry struct {
-	value     interface{}
-	timestamp time.Time
+var cacheLog = aspect.Advice{
+	Type:     aspect.After,
+	Priority: 100,
+	Handler: func(ctx *aspect.Context) error {
+		utils.LogAfter(ctx, 100, "CACHE LOG")
+		if ctx.Skipped {
+			log.Printf("   💾 [CACHE HIT] %s(%v) - returned cached result", ctx.FunctionName, ctx.Args[0])
+		} else {
+			log.Printf("   💾 [CACHE MISS] %s(%v) - executed and cached", ctx.FunctionName, ctx.Args[0])
+		}
+		return nil
+	},
 }
 
 // -------------------------------------------- Domain Models --------------------------------------------
@@ -273,8 +139,8 @@ func example1_BasicCaching() error {
 	fmt.Printf("Second call: %s, took %v (%.1fx faster)\n\n",
 		profile2.Name, duration2, float64(duration1)/float64(duration2))
 
-	hits, miss := userCache.Stats()
-	fmt.Printf("Cache Stats: %d hits, %d miss\n", hits, miss)
+	stats := userCache.Stats("FetchUserProfile")
+	fmt.Printf("Cache Stats: %d hits, %d miss\n", stats.Hits, stats.Misses)
 	return nil
 }
 
@@ -317,11 +183,11 @@ func example3_PerformanceComparison() {
 		totalWithCache += time.Since(start)
 	}
 
-	hits, miss := userCache.Stats()
+	stats := userCache.Stats("FetchUserProfile")
 	fmt.Printf("\nTotal time: %v\n", totalWithCache)
 	fmt.Printf("Average per call: %v\n", totalWithCache/iterations)
 	fmt.Printf("Cache efficiency: %d hits, %d miss (%.1f%% hit rate)\n",
-		hits, miss, float64(hits)/float64(hits+miss)*100)
+		stats.Hits, stats.Misses, stats.HitRatio()*100)
 }
 
 // -------------------------------------------- Main --------------------------------------------
@@ -2,17 +2,30 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/audit"
 	"github.com/seyedali-dev/gosaidsno/examples/utils"
 )
 
 // -------------------------------------------- Auth System --------------------------------------------
 
+// auditPath is the HMAC-chained audit trail written by the audit aspect.
+var auditPath = filepath.Join(os.TempDir(), "gosaidsno-audit.log")
+
+// auditKey chains the audit trail; load it from a secret store in real deployments.
+var auditKey = []byte("example-audit-key")
+
+// auditSink is the trail sink, whose head is kept to verify the file against.
+var auditSink *audit.FileSink
+
 type Session struct {
 	UserID    string
 	Role      string
@@ -42,9 +55,10 @@ func validateToken(token string) (*Session, error) {
 func setupAOP() {
 	log.Println("=== Setting up Authentication AOP ===")
 
-	aspect.MustRegister("GetUserData")
-	aspect.MustRegister("DeleteUser")
-	aspect.MustRegister("UpdateSettings")
+	// The session token (first argument) never shows up in advice that records arguments
+	aspect.MustRegister("GetUserData", aspect.WithRedactedArgs(0))
+	aspect.MustRegister("DeleteUser", aspect.WithRedactedArgs(0))
+	aspect.MustRegister("UpdateSettings", aspect.WithRedactedArgs(0))
 
 	// Authentication check (Before advice, priority 100)
 	for _, fn := range []string{"GetUserData", "DeleteUser", "UpdateSettings"} {
@@ -108,7 +122,7 @@ func setupAOP() {
 				log.Printf("   📋 [AUDIT] Function: %s", ctx.FunctionName)
 				log.Printf("   👤 [AUDIT] User: %s", userID)
 				log.Printf("   📊 [AUDIT] Status: %s", status)
-				log.Printf("   🎯 [AUDIT] Args: %v", ctx.Args[1:])
+				log.Printf("   🎯 [AUDIT] Args: %v", aspect.Redacted(ctx).Args)
 				if ctx.Error != nil {
 					log.Printf("   ❌ [AUDIT] Error: %v", ctx.Error)
 				}
@@ -118,6 +132,19 @@ func setupAOP() {
 		})
 	}
 
+	// Tamper-evident audit trail, written after the console audit log
+	// The file outlives the example, so the head of earlier runs is unknown here
+	sink, err := audit.OpenFile(auditPath, auditKey, audit.Head{})
+	if err != nil {
+		log.Fatalf("opening audit trail: %v", err)
+	}
+	auditSink = sink
+	aspect.MustApply(aspect.On("DeleteUser", "UpdateSettings"), audit.New(audit.Config{
+		Priority: 50,
+		Sink:     sink,
+		ActorKey: "userID",
+	}))
+
 	// Success logging for GetUserData
 	aspect.MustAddAdvice("GetUserData", aspect.Advice{
 		Type:     aspect.AfterReturning,
@@ -249,6 +276,13 @@ func example6_AuditLogging() {
 	}
 
 	fmt.Println("✅ Settings updated (check audit log above)")
+
+	head, err := audit.VerifyFile(auditPath, auditKey, auditSink.Head())
+	if err != nil {
+		fmt.Printf("❌ Audit trail verification failed: %v\n", err)
+		return
+	}
+	fmt.Printf("🔒 Audit trail %s verified: %d entries, head %s\n", auditPath, head.Sequence, head.Hash[:12])
 }
 
 // -------------------------------------------- Main --------------------------------------------
@@ -263,5 +297,7 @@ func main() {
 	example5_AuthorizationFailure()
 	example6_AuditLogging()
 
+	_ = aspect.Shutdown(context.Background())
+	_ = auditSink.Close()
 	fmt.Println("\n========== Authentication Examples Complete ==========")
 }
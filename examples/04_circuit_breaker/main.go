@@ -5,36 +5,25 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"sync"
 	"time"
 
 	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/circuitbreaker"
 	"github.com/seyedali-dev/gosaidsno/examples/utils"
 )
 
 // -------------------------------------------- Circuit Breaker --------------------------------------------
 
-type CircuitBreaker struct {
-	mu              sync.RWMutex
-	failures        int
-	maxFailures     int
-	state           string // CLOSED, OPEN, HALF_OPEN
-	openedAt        time.Time
-	resetTimeout    time.Duration
-	successesNeeded int
-	successes       int
-}
-
-func NewCircuitBreaker(maxFailures int, resetTimeout time.Duration) *CircuitBreaker {
-	return &CircuitBreaker{
-		maxFailures:     maxFailures,
-		state:           "CLOSED",
-		resetTimeout:    resetTimeout,
-		successesNeeded: 2,
-	}
-}
-
-var externalServiceCircuit = NewCircuitBreaker(3, 5*time.Second)
+// externalServiceCircuit opens after 3 consecutive failures, probes after 5s and closes after 2 successful probes
+var externalServiceCircuit = circuitbreaker.New(circuitbreaker.Config{
+	Priority:          100,
+	Policy:            circuitbreaker.ConsecutiveFailures(3),
+	OpenTimeout:       5 * time.Second,
+	HalfOpenSuccesses: 2,
+	OnStateChange: func(change circuitbreaker.StateChange) {
+		log.Printf("   🔄 [CIRCUIT] %s: %s -> %s", change.Key, change.From, change.To)
+	},
+})
 
 // -------------------------------------------- Setup --------------------------------------------
 
@@ -44,102 +33,21 @@ func setupAOP() {
 	aspect.MustRegister("CallExternalService")
 
 	// Around advice: circuit breaker
-	aspect.MustAddAdvice("CallExternalService", aspect.Advice{
-		Type:     aspect.Around,
-		Priority: 100,
-		Handler: func(ctx *aspect.Context) error {
-			utils.LogAround(ctx, 100, "CIRCUIT BREAKER")
-
-			// Check circuit state
-			externalServiceCircuit.mu.Lock()
-			state := externalServiceCircuit.state
-			failures := externalServiceCircuit.failures
-			maxFailures := externalServiceCircuit.maxFailures
-
-			// Check if we should transition from OPEN to HALF_OPEN
-			if state == "OPEN" && time.Since(externalServiceCircuit.openedAt) > externalServiceCircuit.resetTimeout {
-				log.Printf("   🔄 [CIRCUIT] Transitioning OPEN -> HALF_OPEN (timeout elapsed)")
-				externalServiceCircuit.state = "HALF_OPEN"
-				externalServiceCircuit.successes = 0
-				state = "HALF_OPEN"
-			}
-			externalServiceCircuit.mu.Unlock()
-
-			log.Printf("   🔌 [CIRCUIT] Current state: %s, Failures: %d/%d", state, failures, maxFailures)
-
-			if state == "OPEN" {
-				externalServiceCircuit.mu.RLock()
-				remaining := externalServiceCircuit.resetTimeout - time.Since(externalServiceCircuit.openedAt)
-				externalServiceCircuit.mu.RUnlock()
+	aspect.MustApply(aspect.On("CallExternalService"), externalServiceCircuit)
 
-				log.Printf("   🚫 [CIRCUIT] Rejecting call - circuit OPEN")
-				log.Printf("   ⏳ [CIRCUIT] Retry available in: %v", remaining.Round(time.Second))
-				ctx.SetResult(0, "")
-				ctx.Error = fmt.Errorf("circuit breaker OPEN, retry in %v", remaining.Round(time.Second))
-				ctx.Skipped = true
-				utils.LogAround(ctx, 100, "END (circuit open, execution blocked)")
-				return nil
-			}
-
-			if state == "HALF_OPEN" {
-				log.Printf("   ⚠️  [CIRCUIT] Circuit in HALF_OPEN state - testing service recovery")
-			} else {
-				log.Printf("   ✅ [CIRCUIT] Circuit CLOSED - allowing execution")
-			}
-
-			log.Printf("   ▶️  [CIRCUIT] Proceeding with function execution")
-			utils.LogAround(ctx, 100, "END (allowing execution)")
-			return nil // Allow execution
-		},
-	})
-
-	// After advice: track failures
+	// After advice: report rejected calls
 	aspect.MustAddAdvice("CallExternalService", aspect.Advice{
 		Type:     aspect.After,
 		Priority: 100,
 		Handler: func(ctx *aspect.Context) error {
 			utils.LogAfter(ctx, 100, "CIRCUIT METRICS")
 
-			if ctx.Skipped {
-				log.Printf("   ⏭️  [CIRCUIT] Execution was skipped (circuit open)")
+			var openErr *circuitbreaker.OpenError
+			if errors.As(ctx.Error, &openErr) {
+				log.Printf("   🚫 [CIRCUIT] Call rejected - retry available in: %v", openErr.RetryAfter.Round(time.Second))
 				return nil
 			}
-
-			if ctx.Error != nil {
-				externalServiceCircuit.mu.Lock()
-				externalServiceCircuit.failures++
-				currentFailures := externalServiceCircuit.failures
-				maxFailures := externalServiceCircuit.maxFailures
-				log.Printf("   ❌ [CIRCUIT] Failure recorded (%d/%d)", currentFailures, maxFailures)
-
-				if currentFailures >= maxFailures {
-					externalServiceCircuit.state = "OPEN"
-					externalServiceCircuit.openedAt = time.Now()
-					log.Printf("   🚨 [CIRCUIT] Circuit OPENED - too many failures")
-					log.Printf("   ⏰ [CIRCUIT] Reset timeout started: %v", externalServiceCircuit.resetTimeout)
-				}
-				externalServiceCircuit.mu.Unlock()
-			} else {
-				// Success
-				externalServiceCircuit.mu.Lock()
-				if externalServiceCircuit.state == "HALF_OPEN" {
-					externalServiceCircuit.successes++
-					currentSuccesses := externalServiceCircuit.successes
-					neededSuccesses := externalServiceCircuit.successesNeeded
-					log.Printf("   ✅ [CIRCUIT] Success in HALF_OPEN (%d/%d)", currentSuccesses, neededSuccesses)
-
-					if currentSuccesses >= neededSuccesses {
-						externalServiceCircuit.state = "CLOSED"
-						externalServiceCircuit.failures = 0
-						log.Printf("   🔄 [CIRCUIT] Circuit CLOSED - service recovered")
-						log.Printf("   🎉 [CIRCUIT] Service is healthy again!")
-					}
-				} else {
-					externalServiceCircuit.failures = 0
-					log.Printf("   🔧 [CIRCUIT] Reset failure count - service is healthy")
-				}
-				externalServiceCircuit.mu.Unlock()
-			}
+			log.Printf("   🔌 [CIRCUIT] Current state: %s", externalServiceCircuit.State(ctx.FunctionName))
 			return nil
 		},
 	})
@@ -232,10 +140,7 @@ func example3_RealWorldScenario() {
 	fmt.Println("\n========== Example 3: Real World Scenario ==========\n")
 
 	// Reset circuit
-	externalServiceCircuit.mu.Lock()
-	externalServiceCircuit.state = "CLOSED"
-	externalServiceCircuit.failures = 0
-	externalServiceCircuit.mu.Unlock()
+	externalServiceCircuit.Reset("CallExternalService")
 
 	simulateFailure = false
 	callCount = 0
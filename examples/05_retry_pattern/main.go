@@ -1,60 +1,52 @@
-// Package main - retry_pattern demonstrates wrapper pattern for automatic retries
+// Package main - retry_pattern demonstrates the retry aspect for automatic retries
 package main
 
 import (
 	"errors"
 	"fmt"
 	"log"
-	"math"
 	"time"
 
 	"github.com/seyedali-dev/gosaidsno/aspect"
+	"github.com/seyedali-dev/gosaidsno/aspect/retry"
 	"github.com/seyedali-dev/gosaidsno/examples/utils"
 )
 
-// -------------------------------------------- Retry Wrapper --------------------------------------------
-
-// WithRetry wraps a function with retry logic
-func WithRetry[T any](fn func() (T, error), maxRetries int, baseDelay time.Duration) func() (T, error) {
-	return func() (T, error) {
-		var result T
-		var err error
-
-		for attempt := 0; attempt <= maxRetries; attempt++ {
-			if attempt > 0 {
-				delay := time.Duration(math.Pow(2, float64(attempt-1))) * baseDelay
-				log.Printf("   🔄 [RETRY] Attempt %d/%d failed, retrying in %v...", attempt, maxRetries, delay)
-				log.Printf("   ⏳ [RETRY] Exponential backoff: 2^%d * %v = %v", attempt-1, baseDelay, delay)
-				time.Sleep(delay)
-			}
-
-			log.Printf("   🎯 [RETRY] Making attempt %d/%d", attempt+1, maxRetries+1)
-			result, err = fn()
-			if err == nil {
-				if attempt > 0 {
-					log.Printf("   ✅ [RETRY] Success on attempt %d/%d", attempt+1, maxRetries+1)
-				} else {
-					log.Printf("   ✅ [RETRY] Success on first attempt")
-				}
-				return result, nil
-			}
-
-			log.Printf("   ❌ [RETRY] Attempt %d/%d failed: %v", attempt+1, maxRetries+1, err)
-		}
-
-		log.Printf("   💥 [RETRY] Exhausted all %d retries", maxRetries)
-		log.Printf("   🚨 [RETRY] Final failure after %d attempts", maxRetries+1)
-		return result, err
-	}
-}
-
 // -------------------------------------------- Setup --------------------------------------------
 
 func setupAOP() {
-	log.Println("=== Setting up Monitoring AOP ===")
+	log.Println("=== Setting up Retry & Monitoring AOP ===")
 
 	aspect.MustRegister("SendEmail")
 	aspect.MustRegister("ProcessPayment")
+	aspect.MustRegister("FailingOperation")
+
+	// Retry advice wraps everything else (highest Around priority), re-invoking the target on failure
+	logRetry := func(ctx *aspect.Context, attempt int, err error, delay time.Duration) {
+		log.Printf("   ❌ [RETRY] %s attempt %d failed: %v", ctx.FunctionName, attempt, err)
+		log.Printf("   🔄 [RETRY] Retrying in %v...", delay)
+	}
+	aspect.MustApply(aspect.On("SendEmail"), retry.New(retry.Config{
+		Name:        "email-retry",
+		Priority:    200,
+		MaxAttempts: 4,
+		Backoff:     retry.Exponential(100*time.Millisecond, time.Second),
+		OnRetry:     logRetry,
+	}))
+	aspect.MustApply(aspect.On("ProcessPayment"), retry.New(retry.Config{
+		Name:        "payment-retry",
+		Priority:    200,
+		MaxAttempts: 6,
+		Backoff:     retry.ExponentialJitter(200*time.Millisecond, 2*time.Second),
+		OnRetry:     logRetry,
+	}))
+	aspect.MustApply(aspect.On("FailingOperation"), retry.New(retry.Config{
+		Name:        "failing-retry",
+		Priority:    200,
+		MaxAttempts: 4,
+		Backoff:     retry.Constant(50 * time.Millisecond),
+		OnRetry:     logRetry,
+	}))
 
 	// Add timing for monitoring
 	for _, fn := range []string{"SendEmail", "ProcessPayment"} {
@@ -163,53 +155,11 @@ func processPaymentImpl(amount float64, cardToken string) (string, error) {
 	return txnID, nil
 }
 
-// -------------------------------------------- Wrapped Functions with Retry --------------------------------------------
-
-// SendEmail with retry wrapper
-func SendEmail(to, subject string) error {
-	log.Printf("🚀 [ENTRY] SendEmail called with retry wrapper")
-	log.Printf("   🔧 [RETRY CONFIG] Max retries: 3, Base delay: 100ms")
+// -------------------------------------------- Wrapped Functions --------------------------------------------
 
-	// Wrap the base function with retry logic
-	retryFn := WithRetry(func() (struct{}, error) {
-		err := sendEmailBase(to, subject)
-		return struct{}{}, err
-	}, 3, 100*time.Millisecond)
+var SendEmail = aspect.Wrap2E("SendEmail", sendEmailImpl)
 
-	_, err := retryFn()
-
-	if err != nil {
-		log.Printf("💥 [EXIT] SendEmail failed after all retries: %v", err)
-	} else {
-		log.Printf("✅ [EXIT] SendEmail completed successfully")
-	}
-
-	return err
-}
-
-var sendEmailBase = aspect.Wrap2E("SendEmail", sendEmailImpl)
-
-// ProcessPayment with retry wrapper
-func ProcessPayment(amount float64, cardToken string) (string, error) {
-	log.Printf("🚀 [ENTRY] ProcessPayment called with retry wrapper")
-	log.Printf("   🔧 [RETRY CONFIG] Max retries: 5, Base delay: 200ms")
-
-	retryFn := WithRetry(func() (string, error) {
-		return processPaymentBase(amount, cardToken)
-	}, 5, 200*time.Millisecond)
-
-	result, err := retryFn()
-
-	if err != nil {
-		log.Printf("💥 [EXIT] ProcessPayment failed after all retries: %v", err)
-	} else {
-		log.Printf("✅ [EXIT] ProcessPayment completed successfully: %s", result)
-	}
-
-	return result, err
-}
-
-var processPaymentBase = aspect.Wrap2RE("ProcessPayment", processPaymentImpl)
+var ProcessPayment = aspect.Wrap2RE("ProcessPayment", processPaymentImpl)
 
 // -------------------------------------------- Examples --------------------------------------------
 
@@ -251,12 +201,12 @@ func example3_ExhaustedRetries() {
 
 	// Function that always fails
 	var failAttempts = 0
-	FailingOperation := WithRetry(func() (string, error) {
+	FailingOperation := aspect.Wrap0RE("FailingOperation", func() (string, error) {
 		failAttempts++
 		log.Printf("   💥 [BUSINESS] FailingOperation executing - attempt #%d", failAttempts)
 		log.Printf("   ❌ [BUSINESS] Permanent failure (simulated)")
 		return "", errors.New("permanent failure")
-	}, 3, 50*time.Millisecond)
+	})
 
 	log.Printf("🚀 [ENTRY] FailingOperation called (will exhaust retries)")
 	start := time.Now()